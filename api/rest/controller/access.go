@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"HotelService/api/rest/middleware"
+	"HotelService/application/service"
+	"HotelService/domain/model"
+	"context"
+	"net/http"
+)
+
+// Guard wraps HotelierController handlers with a permission check against the
+// hotel the request path points at. Its middleware runs after RequireHotelier.
+type Guard struct {
+	access service.AccessService
+}
+
+func NewGuard(access service.AccessService) *Guard {
+	return &Guard{access: access}
+}
+
+// Hotel guards routes whose {id} is a hotel
+func (g *Guard) Hotel(permission model.Permission) middleware.Middleware {
+	return g.guard(permission, g.access.AuthorizeHotel)
+}
+
+// Room guards routes whose {id} is a room
+func (g *Guard) Room(permission model.Permission) middleware.Middleware {
+	return g.guard(permission, g.access.AuthorizeRoom)
+}
+
+// RatePlan guards routes whose {id} is a rate plan
+func (g *Guard) RatePlan(permission model.Permission) middleware.Middleware {
+	return g.guard(permission, g.access.AuthorizeRatePlan)
+}
+
+type authorizeFunc func(ctx context.Context, id int64, permission model.Permission) error
+
+func (g *Guard) guard(permission model.Permission, authorize authorizeFunc) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, ok := pathID(r, "id")
+			if !ok {
+				// Malformed IDs are reported by the handler itself
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := authorize(r.Context(), id, permission); err != nil {
+				writeError(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"HotelService/application/dto"
+	"HotelService/application/service"
+	"HotelService/domain/model"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AuditController struct {
+	auditService service.AuditService
+}
+
+func NewAuditController(auditService service.AuditService) *AuditController {
+	return &AuditController{
+		auditService: auditService,
+	}
+}
+
+// ListAuditLog GET /hotelier/hotels/{id}/audit
+//
+// Filters: actor_id, action, entity_type, entity_id, and since/until as RFC 3339 timestamps
+func (c *AuditController) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	hotelID, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
+		return
+	}
+
+	query := r.URL.Query()
+	page, err := parsePageRequest(query)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+
+	filter := dto.AuditFilter{
+		HotelID:     hotelID,
+		Action:      model.AuditAction(query.Get("action")),
+		EntityType:  model.AuditEntity(query.Get("entity_type")),
+		PageRequest: page,
+	}
+
+	if v := query.Get("actor_id"); v != "" {
+		if filter.ActorID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid actor_id")
+			return
+		}
+	}
+	if v := query.Get("entity_id"); v != "" {
+		if filter.EntityID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid entity_id")
+			return
+		}
+	}
+	if v := query.Get("since"); v != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid since, expected an RFC 3339 timestamp")
+			return
+		}
+	}
+	if v := query.Get("until"); v != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid until, expected an RFC 3339 timestamp")
+			return
+		}
+	}
+
+	entries, err := c.auditService.ListAuditLog(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
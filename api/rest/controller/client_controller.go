@@ -1,13 +1,20 @@
 package controller
 
 import (
+	"HotelService/application/dto"
 	"HotelService/application/service"
+	"HotelService/domain/model"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
+const dateLayout = "2006-01-02"
+
 type ClientController struct {
 	hotelService service.HotelService
 }
@@ -18,16 +25,24 @@ func NewClientController(hotelService service.HotelService) *ClientController {
 	}
 }
 
-// ListHotels GET /client/hotels
+// ListHotels GET /client/hotels?name=&address=&sort=&limit=&cursor=
 func (c *ClientController) ListHotels(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	query := r.URL.Query()
+	page, err := parsePageRequest(query)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid limit")
 		return
 	}
 
-	hotels, err := c.hotelService.ListHotels(r.Context())
+	filter := dto.HotelFilter{
+		Name:        query.Get("name"),
+		Address:     query.Get("address"),
+		PageRequest: page,
+	}
+
+	hotels, err := c.hotelService.ListHotels(r.Context(), filter)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -37,38 +52,73 @@ func (c *ClientController) ListHotels(w http.ResponseWriter, r *http.Request) {
 
 // GetHotelDetails GET /client/hotels/{id}
 func (c *ClientController) GetHotelDetails(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	path := strings.TrimPrefix(r.URL.Path, "/client/hotels/")
-	id, err := strconv.ParseInt(path, 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
 		return
 	}
 
 	hotel, err := c.hotelService.GetHotel(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeError(w, r, err)
 		return
 	}
 
+	setETag(w, hotel.Version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(hotel)
 }
 
-// FindAvailableRooms GET /client/rooms/available
+// FindAvailableRooms GET /client/rooms/available?check_in=&check_out=&guests=&hotel_id=&type=&min_price=&max_price=&currency=&sort=&limit=&cursor=
 func (c *ClientController) FindAvailableRooms(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	query := r.URL.Query()
+	page, err := parsePageRequest(query)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+
+	search := dto.StaySearch{
+		Type:        query.Get("type"),
+		PageRequest: page,
+	}
+
+	if v := query.Get("check_in"); v != "" {
+		if search.CheckIn, err = time.Parse(dateLayout, v); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid check_in date, expected YYYY-MM-DD")
+			return
+		}
+	}
+	if v := query.Get("check_out"); v != "" {
+		if search.CheckOut, err = time.Parse(dateLayout, v); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid check_out date, expected YYYY-MM-DD")
+			return
+		}
+	}
+	if v := query.Get("guests"); v != "" {
+		if search.Guests, err = strconv.Atoi(v); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid guests")
+			return
+		}
+	}
+	if v := query.Get("hotel_id"); v != "" {
+		if search.HotelID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
+			return
+		}
+	}
+	if search.MinPrice, err = parsePriceFilter(query, "min_price"); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid min_price: "+err.Error())
+		return
+	}
+	if search.MaxPrice, err = parsePriceFilter(query, "max_price"); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid max_price: "+err.Error())
 		return
 	}
 
-	rooms, err := c.hotelService.FindAvailableRooms(r.Context())
+	rooms, err := c.hotelService.FindAvailableRooms(r.Context(), search)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -76,6 +126,160 @@ func (c *ClientController) FindAvailableRooms(w http.ResponseWriter, r *http.Req
 	json.NewEncoder(w).Encode(rooms)
 }
 
+// QuoteStay GET /client/rooms/{id}/quote?check_in=&check_out=&rate_plan_id=
+func (c *ClientController) QuoteStay(w http.ResponseWriter, r *http.Request) {
+	roomID, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid room ID")
+		return
+	}
+
+	query := r.URL.Query()
+	checkIn, err := time.Parse(dateLayout, query.Get("check_in"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid check_in date, expected YYYY-MM-DD")
+		return
+	}
+	checkOut, err := time.Parse(dateLayout, query.Get("check_out"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid check_out date, expected YYYY-MM-DD")
+		return
+	}
+
+	var ratePlanID int64
+	if v := query.Get("rate_plan_id"); v != "" {
+		if ratePlanID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid rate plan ID")
+			return
+		}
+	}
+
+	quote, err := c.hotelService.QuoteStay(r.Context(), roomID, ratePlanID, checkIn, checkOut)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
+
+type CreateReservationRequest struct {
+	RoomID     int64  `json:"room_id"`
+	GuestName  string `json:"guest_name"`
+	GuestEmail string `json:"guest_email"`
+	CheckIn    string `json:"check_in"`
+	CheckOut   string `json:"check_out"`
+}
+
+// CreateReservation POST /client/reservations
+func (c *ClientController) CreateReservation(w http.ResponseWriter, r *http.Request) {
+	var req CreateReservationRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	checkIn, err := time.Parse(dateLayout, req.CheckIn)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid check_in date, expected YYYY-MM-DD")
+		return
+	}
+	checkOut, err := time.Parse(dateLayout, req.CheckOut)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid check_out date, expected YYYY-MM-DD")
+		return
+	}
+
+	reservation, err := c.hotelService.CreateReservation(r.Context(), req.RoomID, req.GuestName, req.GuestEmail, checkIn, checkOut)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// GetReservation GET /client/reservations/{id}?code= or ?guest_email=
+func (c *ClientController) GetReservation(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid reservation ID")
+		return
+	}
+
+	reservation, err := c.hotelService.GetReservation(r.Context(), id, reservationAccess(r.URL.Query()))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// CancelReservation DELETE /client/reservations/{id}?code= or ?guest_email=
+func (c *ClientController) CancelReservation(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid reservation ID")
+		return
+	}
+
+	if err := c.hotelService.CancelReservation(r.Context(), id, reservationAccess(r.URL.Query())); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reservationAccess reads what the guest presents to reach a booking
+func reservationAccess(query url.Values) dto.ReservationAccess {
+	return dto.ReservationAccess{
+		Code:       strings.ToUpper(query.Get("code")),
+		GuestEmail: query.Get("guest_email"),
+	}
+}
+
+// parsePriceFilter reads an optional decimal price bound in the "currency" query parameter
+func parsePriceFilter(query url.Values, key string) (*model.Money, error) {
+	v := query.Get(key)
+	if v == "" {
+		return nil, nil
+	}
+
+	currency := strings.ToUpper(query.Get("currency"))
+	if currency == "" {
+		return nil, errors.New("currency is required with price filters")
+	}
+
+	price, err := model.ParseMoney(v, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &price, nil
+}
+
+func parsePageRequest(query url.Values) (dto.PageRequest, error) {
+	page := dto.PageRequest{
+		Sort:   query.Get("sort"),
+		Cursor: query.Get("cursor"),
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return page, err
+		}
+		page.Limit = limit
+	}
+
+	return page, nil
+}
+
 // test
 func (c *ClientController) writeJson(w http.ResponseWriter, data interface{}) error {
 	w.Header().Set("Content-Type", "application/json")
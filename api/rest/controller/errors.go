@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"HotelService/api/rest/middleware"
+	"HotelService/domain/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// Problem is an RFC 7807 problem details body
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Errors lists invalid request fields so forms can highlight each of them
+	Errors []model.FieldError `json:"errors,omitempty"`
+}
+
+// writeProblem answers with an application/problem+json body
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	encodeProblem(w, newProblem(r, status, detail))
+}
+
+func newProblem(r *http.Request, status int, detail string) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	}
+}
+
+func encodeProblem(w http.ResponseWriter, problem Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// writeError maps a service error onto a status code. Only messages of classified
+// domain errors reach the client; anything else is logged and reported as a 500.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		// The request ran out of time; see middleware.Timeout
+		logError(r, err)
+		writeProblem(w, r, http.StatusServiceUnavailable, "The request timed out")
+		return
+	case errors.Is(err, model.ErrInternal):
+	case errors.Is(err, model.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, model.ErrValidation):
+		status = http.StatusBadRequest
+	case errors.Is(err, model.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, model.ErrPreconditionFailed):
+		status = http.StatusPreconditionFailed
+	case errors.Is(err, model.ErrUnprocessable):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, model.ErrUnauthenticated):
+		status = http.StatusUnauthorized
+	case errors.Is(err, model.ErrForbidden):
+		status = http.StatusForbidden
+	}
+
+	if status == http.StatusInternalServerError {
+		logError(r, err)
+		writeProblem(w, r, status, "An unexpected error occurred")
+		return
+	}
+
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		problem := newProblem(r, status, "One or more fields are invalid")
+		problem.Errors = validationErr.Fields
+		encodeProblem(w, problem)
+		return
+	}
+
+	writeProblem(w, r, status, publicMessage(err, status))
+}
+
+func publicMessage(err error, status int) string {
+	var domainErr *model.Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+	return http.StatusText(status)
+}
+
+// logError records an error that is not shown to the client
+func logError(r *http.Request, err error) {
+	slog.ErrorContext(r.Context(), "request failed",
+		slog.String("request_id", middleware.RequestIDFromContext(r.Context())),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("error", err.Error()),
+	)
+}
+
+// decodeJSON reads the request body into dst. It answers the request itself and
+// returns false when the body is not valid JSON or is too large.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		writeBodyError(w, r, err, "Invalid JSON")
+		return false
+	}
+	return true
+}
+
+// writeBodyError reports a failed read of the request body, with 413 when the
+// body exceeded middleware.MaxBodySize
+func writeBodyError(w http.ResponseWriter, r *http.Request, err error, detail string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeProblem(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body must be at most %d bytes", tooLarge.Limit))
+		return
+	}
+	writeProblem(w, r, http.StatusBadRequest, detail)
+}
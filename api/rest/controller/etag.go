@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"HotelService/application/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// setETag tags the response with the version of the hotel or room in its body.
+// A hotel's version also changes with its rooms, so its ETag covers them.
+func setETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// ifMatch reads the version a write is based on from the If-Match header. Writes
+// must name the ETag they replace, or "*" to skip the check. It answers the
+// request itself and returns false when the header is missing or can never match.
+func ifMatch(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		writeProblem(w, r, http.StatusPreconditionRequired, "If-Match header with the ETag of the resource is required")
+		return 0, false
+	}
+	if header == "*" {
+		return service.AnyVersion, true
+	}
+
+	// Only a single strong ETag of ours can match; weak tags and lists never do
+	if tag, ok := strings.CutPrefix(header, `"`); ok {
+		if tag, ok := strings.CutSuffix(tag, `"`); ok {
+			if version, err := strconv.ParseInt(tag, 10, 64); err == nil && version > 0 {
+				return version, true
+			}
+		}
+	}
+
+	writeProblem(w, r, http.StatusPreconditionFailed, "If-Match does not match the current version")
+	return 0, false
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// readinessTimeout bounds the storage checks of one /readyz probe
+const readinessTimeout = 2 * time.Second
+
+// Pinger is the storage connection /readyz checks, e.g. a *sql.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// SchemaVersioner reports how far the schema is migrated, e.g. a *db.Migrator
+type SchemaVersioner interface {
+	SchemaVersion(ctx context.Context) (applied, latest int64, err error)
+}
+
+// BuildInfo describes the running binary
+type BuildInfo struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// HealthController answers the probes of the orchestrator. Database and Schema
+// are nil for in-memory storage, which is always ready.
+type HealthController struct {
+	database Pinger
+	schema   SchemaVersioner
+	build    BuildInfo
+	draining <-chan struct{}
+}
+
+func NewHealthController(database Pinger, schema SchemaVersioner, build BuildInfo, draining <-chan struct{}) *HealthController {
+	return &HealthController{
+		database: database,
+		schema:   schema,
+		build:    build,
+		draining: draining,
+	}
+}
+
+type HealthResponse struct {
+	Status string `json:"status"`
+
+	// Checks maps each failed readiness check to the reason
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type VersionResponse struct {
+	BuildInfo
+
+	// SchemaVersion is the newest applied migration, omitted when unknown
+	SchemaVersion int64 `json:"schema_version,omitempty"`
+}
+
+// Health GET /healthz reports that the process is alive and serving
+func (c *HealthController) Health(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(HealthResponse{Status: "ok"})
+}
+
+// Ready GET /readyz reports whether the server should receive traffic: the
+// database answers, its schema is fully migrated, and no shutdown is under way
+func (c *HealthController) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	checks := make(map[string]string)
+	select {
+	case <-c.draining:
+		checks["shutdown"] = "the server is shutting down"
+	default:
+	}
+	if c.database != nil {
+		if err := c.database.PingContext(ctx); err != nil {
+			slog.WarnContext(ctx, "readiness check failed", slog.String("check", "database"), slog.String("error", err.Error()))
+			checks["database"] = "the database is unreachable"
+		}
+	}
+	if c.schema != nil {
+		applied, latest, err := c.schema.SchemaVersion(ctx)
+		if err != nil {
+			slog.WarnContext(ctx, "readiness check failed", slog.String("check", "migrations"), slog.String("error", err.Error()))
+			checks["migrations"] = "the schema version is unknown"
+		} else if applied < latest {
+			checks["migrations"] = fmt.Sprintf("the schema is at version %d of %d", applied, latest)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if len(checks) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(HealthResponse{Status: "unavailable", Checks: checks})
+		return
+	}
+	json.NewEncoder(w).Encode(HealthResponse{Status: "ready"})
+}
+
+// Version GET /version describes the running build and the schema it serves
+func (c *HealthController) Version(w http.ResponseWriter, r *http.Request) {
+	response := VersionResponse{BuildInfo: c.build}
+	if c.schema != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		defer cancel()
+
+		if applied, _, err := c.schema.SchemaVersion(ctx); err == nil {
+			response.SchemaVersion = applied
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
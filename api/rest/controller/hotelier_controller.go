@@ -3,10 +3,11 @@ package controller
 import (
 	"HotelService/application/dto"
 	"HotelService/application/service"
+	"HotelService/application/validation"
+	"HotelService/domain/model"
 	"encoding/json"
 	"net/http"
-	"strconv"
-	"strings"
+	"time"
 )
 
 type HotelierController struct {
@@ -20,44 +21,70 @@ func NewHotelierController(hotelService service.HotelService) *HotelierControlle
 }
 
 type CreateHotelRequest struct {
-	Name    string              `json:"name"`
-	Address string              `json:"address"`
-	Rooms   []CreateRoomRequest `json:"rooms"`
+	Name     string              `json:"name"`
+	Address  string              `json:"address"`
+	Currency string              `json:"currency"`
+	Rooms    []CreateRoomRequest `json:"rooms"`
 }
 
 type CreateRoomRequest struct {
-	Number    string  `json:"number"`
-	Type      string  `json:"type"`
-	Price     float64 `json:"price"`
-	Available bool    `json:"available"`
+	Number    string       `json:"number"`
+	Type      string       `json:"type"`
+	Price     PriceRequest `json:"price"`
+	Capacity  int          `json:"capacity"`
+	Available bool         `json:"available"`
 }
 
-// CreateHotel POST /hotelier/hotels
-func (c *HotelierController) CreateHotel(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+// PriceRequest is a price as the client sent it. The amount, quoted or not, is
+// kept as text so that a malformed amount or an unknown currency is reported as
+// an error on the price field rather than as invalid JSON.
+type PriceRequest struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// money parses the price, recording any problem for field in v. A price that was
+// left out is returned as zero for the service to reject.
+func (p PriceRequest) money(v *validation.Validator, field string) model.Money {
+	if p.Amount == nil && p.Currency == "" {
+		return model.Money{}
+	}
+
+	amount := string(p.Amount)
+	var quoted string
+	if json.Unmarshal(p.Amount, &quoted) == nil {
+		amount = quoted
 	}
 
+	return v.Money(field, amount, p.Currency)
+}
+
+// CreateHotel POST /hotelier/hotels
+func (c *HotelierController) CreateHotel(w http.ResponseWriter, r *http.Request) {
 	var req CreateHotelRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
+	v := validation.New()
 	rooms := make([]dto.RoomInput, len(req.Rooms))
 	for i, room := range req.Rooms {
 		rooms[i] = dto.RoomInput{
 			Number:    room.Number,
 			Type:      room.Type,
-			Price:     room.Price,
+			Price:     room.Price.money(v, validation.Field("rooms", i, "price")),
+			Capacity:  room.Capacity,
 			Available: room.Available,
 		}
 	}
+	if err := v.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
 
-	hotel, err := c.hotelService.CreateHotel(r.Context(), req.Name, req.Address, rooms)
+	hotel, err := c.hotelService.CreateHotel(r.Context(), req.Name, req.Address, req.Currency, rooms)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
@@ -73,94 +100,115 @@ type UpdateHotelRequest struct {
 
 // UpdateHotel PUT /hotelier/hotels/{id}
 func (c *HotelierController) UpdateHotel(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
 		return
 	}
 
-	path := strings.TrimPrefix(r.URL.Path, "/hotelier/hotels/")
-	id, err := strconv.ParseInt(path, 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+	version, ok := ifMatch(w, r)
+	if !ok {
 		return
 	}
 
 	var req UpdateHotelRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	hotel, err := c.hotelService.UpdateHotel(r.Context(), id, req.Name, req.Address)
+	hotel, err := c.hotelService.UpdateHotel(r.Context(), id, version, req.Name, req.Address)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
+	setETag(w, hotel.Version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(hotel)
 }
 
-// GetHotel GET /hotelier/hotels/{id}
-func (c *HotelierController) GetHotel(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// PatchHotel PATCH /hotelier/hotels/{id} with a merge patch of name and address
+func (c *HotelierController) PatchHotel(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
 		return
 	}
 
-	path := strings.TrimPrefix(r.URL.Path, "/hotelier/hotels/")
-	id, err := strconv.ParseInt(path, 10, 64)
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	var patch dto.HotelPatch
+	if !decodeMergePatch(w, r, map[string]interface{}{
+		"name":    &patch.Name,
+		"address": &patch.Address,
+	}) {
+		return
+	}
+
+	hotel, err := c.hotelService.PatchHotel(r.Context(), id, version, patch)
 	if err != nil {
-		http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, hotel.Version)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hotel)
+}
+
+// GetHotel GET /hotelier/hotels/{id}
+func (c *HotelierController) GetHotel(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
 		return
 	}
 
 	hotel, err := c.hotelService.GetHotel(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeError(w, r, err)
 		return
 	}
 
+	setETag(w, hotel.Version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(hotel)
 }
 
 type AddRoomRequest struct {
-	Number    string  `json:"number"`
-	Type      string  `json:"type"`
-	Price     float64 `json:"price"`
-	Available bool    `json:"available"`
+	Number    string       `json:"number"`
+	Type      string       `json:"type"`
+	Price     PriceRequest `json:"price"`
+	Capacity  int          `json:"capacity"`
+	Available bool         `json:"available"`
 }
 
-// AddRoom POST /hotelier/hotels/{hotelId}/rooms
+// AddRoom POST /hotelier/hotels/{id}/rooms
 func (c *HotelierController) AddRoom(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	path := strings.TrimPrefix(r.URL.Path, "/hotelier/hotels/")
-	parts := strings.Split(path, "/")
-	if len(parts) < 2 || parts[1] != "rooms" {
-		http.Error(w, "Invalid URL path", http.StatusBadRequest)
+	hotelID, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
 		return
 	}
 
-	hotelID, err := strconv.ParseInt(parts[0], 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+	var req AddRoomRequest
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	var req AddRoomRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	v := validation.New()
+	price := req.Price.money(v, "price")
+	if err := v.Err(); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	room, err := c.hotelService.AddRoomToHotel(r.Context(), hotelID, req.Number, req.Type, req.Price, req.Available)
+	room, err := c.hotelService.AddRoomToHotel(r.Context(), hotelID, req.Number, req.Type, price, req.Capacity, req.Available)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
@@ -170,58 +218,168 @@ func (c *HotelierController) AddRoom(w http.ResponseWriter, r *http.Request) {
 }
 
 type UpdateRoomRequest struct {
-	Number    string  `json:"number"`
-	Type      string  `json:"type"`
-	Price     float64 `json:"price"`
-	Available bool    `json:"available"`
+	Number    string       `json:"number"`
+	Type      string       `json:"type"`
+	Price     PriceRequest `json:"price"`
+	Capacity  int          `json:"capacity"`
+	Available bool         `json:"available"`
+}
+
+// GetRoom GET /hotelier/rooms/{id}
+func (c *HotelierController) GetRoom(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid room ID")
+		return
+	}
+
+	room, err := c.hotelService.GetRoom(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, room.Version)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(room)
 }
 
 // UpdateRoom PUT /hotelier/rooms/{id}
 func (c *HotelierController) UpdateRoom(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid room ID")
 		return
 	}
 
-	path := strings.TrimPrefix(r.URL.Path, "/hotelier/rooms/")
-	id, err := strconv.ParseInt(path, 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid room ID", http.StatusBadRequest)
+	version, ok := ifMatch(w, r)
+	if !ok {
 		return
 	}
 
 	var req UpdateRoomRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	v := validation.New()
+	price := req.Price.money(v, "price")
+	if err := v.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	room, err := c.hotelService.UpdateRoom(r.Context(), id, version, req.Number, req.Type, price, req.Capacity, req.Available)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, room.Version)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(room)
+}
+
+// PatchRoom PATCH /hotelier/rooms/{id} with a merge patch of the room fields.
+// A price is replaced as a whole, so it needs both amount and currency.
+func (c *HotelierController) PatchRoom(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid room ID")
+		return
+	}
+
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	var patch dto.RoomPatch
+	var price *PriceRequest
+	if !decodeMergePatch(w, r, map[string]interface{}{
+		"number":    &patch.Number,
+		"type":      &patch.Type,
+		"price":     &price,
+		"capacity":  &patch.Capacity,
+		"available": &patch.Available,
+	}) {
 		return
 	}
+	if price != nil {
+		v := validation.New()
+		money := price.money(v, "price")
+		if err := v.Err(); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		patch.Price = &money
+	}
 
-	room, err := c.hotelService.UpdateRoom(r.Context(), id, req.Number, req.Type, req.Price, req.Available)
+	room, err := c.hotelService.PatchRoom(r.Context(), id, version, patch)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
+	setETag(w, room.Version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(room)
 }
 
+// DeleteHotel soft-deletes the hotel; RestoreHotel undoes it until the hotel is purged
+func (c *HotelierController) DeleteHotel(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
+		return
+	}
+
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.hotelService.DeleteHotel(r.Context(), id, version); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *HotelierController) RestoreHotel(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
+		return
+	}
+
+	hotel, err := c.hotelService.RestoreHotel(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, hotel.Version)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hotel)
+}
+
 // DeleteRoom DELETE /hotelier/rooms/{id}
 func (c *HotelierController) DeleteRoom(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid room ID")
 		return
 	}
 
-	path := strings.TrimPrefix(r.URL.Path, "/hotelier/rooms/")
-	id, err := strconv.ParseInt(path, 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid room ID", http.StatusBadRequest)
+	version, ok := ifMatch(w, r)
+	if !ok {
 		return
 	}
 
-	if err := c.hotelService.DeleteRoom(r.Context(), id); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := c.hotelService.DeleteRoom(r.Context(), id, version); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -234,32 +392,199 @@ type UpdateRoomAvailabilityRequest struct {
 
 // UpdateRoomAvailability PATCH /hotelier/rooms/{id}/availability
 func (c *HotelierController) UpdateRoomAvailability(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPatch {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	roomID, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid room ID")
+		return
+	}
+
+	version, ok := ifMatch(w, r)
+	if !ok {
 		return
 	}
 
-	path := strings.TrimPrefix(r.URL.Path, "/hotelier/rooms/")
-	parts := strings.Split(path, "/")
-	if len(parts) < 2 || parts[1] != "availability" {
-		http.Error(w, "Invalid URL path", http.StatusBadRequest)
+	var req UpdateRoomAvailabilityRequest
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	roomID, err := strconv.ParseInt(parts[0], 10, 64)
+	room, err := c.hotelService.UpdateRoomAvailability(r.Context(), roomID, version, req.Available)
 	if err != nil {
-		http.Error(w, "Invalid room ID", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	var req UpdateRoomAvailabilityRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	setETag(w, room.Version)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListRoomReservations GET /hotelier/rooms/{id}/reservations
+func (c *HotelierController) ListRoomReservations(w http.ResponseWriter, r *http.Request) {
+	roomID, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid room ID")
+		return
+	}
+
+	reservations, err := c.hotelService.ListRoomReservations(r.Context(), roomID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reservations)
+}
+
+type CreateRatePlanRequest struct {
+	Name string `json:"name"`
+}
+
+// CreateRatePlan POST /hotelier/hotels/{id}/rate-plans
+func (c *HotelierController) CreateRatePlan(w http.ResponseWriter, r *http.Request) {
+	hotelID, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
+		return
+	}
+
+	var req CreateRatePlanRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	plan, err := c.hotelService.CreateRatePlan(r.Context(), hotelID, req.Name)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(plan)
+}
+
+// ListRatePlans GET /hotelier/hotels/{id}/rate-plans
+func (c *HotelierController) ListRatePlans(w http.ResponseWriter, r *http.Request) {
+	hotelID, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
+		return
+	}
+
+	plans, err := c.hotelService.ListRatePlans(r.Context(), hotelID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plans)
+}
+
+// GetRatePlan GET /hotelier/rate-plans/{id}
+func (c *HotelierController) GetRatePlan(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid rate plan ID")
+		return
+	}
+
+	plan, err := c.hotelService.GetRatePlan(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plan)
+}
+
+type AddSeasonalRateRequest struct {
+	RoomID    *int64       `json:"room_id"`
+	RoomType  string       `json:"room_type"`
+	StartDate string       `json:"start_date"`
+	EndDate   string       `json:"end_date"`
+	Price     PriceRequest `json:"price"`
+}
+
+// AddSeasonalRate POST /hotelier/rate-plans/{id}/seasons
+func (c *HotelierController) AddSeasonalRate(w http.ResponseWriter, r *http.Request) {
+	planID, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid rate plan ID")
+		return
+	}
+
+	var req AddSeasonalRateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	startDate, err := time.Parse(dateLayout, req.StartDate)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid start_date, expected YYYY-MM-DD")
+		return
+	}
+	endDate, err := time.Parse(dateLayout, req.EndDate)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid end_date, expected YYYY-MM-DD")
+		return
+	}
+
+	v := validation.New()
+	price := req.Price.money(v, "price")
+	if err := v.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	rate, err := c.hotelService.AddSeasonalRate(r.Context(), planID, dto.SeasonalRateInput{
+		RoomID:    req.RoomID,
+		RoomType:  req.RoomType,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Price:     price,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rate)
+}
+
+// SetDayModifierRequest takes the weekday by reference, as 0 is Sunday rather
+// than a weekday left out
+type SetDayModifierRequest struct {
+	Weekday    *int    `json:"weekday"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// SetDayModifier PUT /hotelier/rate-plans/{id}/day-modifiers
+func (c *HotelierController) SetDayModifier(w http.ResponseWriter, r *http.Request) {
+	planID, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid rate plan ID")
+		return
+	}
+
+	var req SetDayModifierRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	v := validation.New()
+	v.Check(req.Weekday != nil, "weekday", "is required")
+	if err := v.Err(); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := c.hotelService.UpdateRoomAvailability(r.Context(), roomID, req.Available); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := c.hotelService.SetDayModifier(r.Context(), planID, time.Weekday(*req.Weekday), req.Multiplier); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"HotelService/api/rest/middleware"
+	"HotelService/application/service"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+)
+
+// idempotent lets hoteliers retry a create safely. A request sent with an
+// Idempotency-Key header runs once; retries with the same key and payload get
+// the original response replayed, and reusing the key for another payload is
+// rejected with 422. Requests without the header run as before.
+func idempotent(idempotency service.IdempotencyService) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("Idempotency-Key")
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeBodyError(w, r, err, "Invalid request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			earlier, err := idempotency.Begin(r.Context(), key, requestHash(r, body))
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+			if earlier != nil {
+				if earlier.ContentType != "" {
+					w.Header().Set("Content-Type", earlier.ContentType)
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(earlier.StatusCode)
+				w.Write(earlier.Body)
+				return
+			}
+
+			// The response is stored even if the client is gone; that is when it retries
+			ctx := context.WithoutCancel(r.Context())
+
+			// A key left claimed would answer every retry with 409 until it
+			// expires, so it is released when the handler panics or the
+			// response cannot be stored
+			release := func() {
+				if err := idempotency.Release(ctx, key); err != nil {
+					logError(r, err)
+				}
+			}
+			defer func() {
+				if p := recover(); p != nil {
+					release()
+					panic(p)
+				}
+			}()
+
+			recorder := &recordingWriter{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+
+			if err := idempotency.Finish(ctx, key, recorder.statusCode(), recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+				logError(r, err)
+				release()
+			}
+		})
+	}
+}
+
+// requestHash fingerprints what a retry must repeat: the method, path and body
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter passes a response through while keeping a copy of it
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
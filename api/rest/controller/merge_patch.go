@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"HotelService/application/validation"
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"sort"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+// decodeMergePatch reads an RFC 7396 merge patch into fields, which maps each
+// member the resource accepts to a pointer that is allocated when the member is
+// present. Members are replaced as a whole, and since none of them is optional
+// a null (removing the member) is rejected like an unknown member. It answers
+// the request itself and returns false when the patch cannot be applied.
+func decodeMergePatch(w http.ResponseWriter, r *http.Request, fields map[string]interface{}) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != mergePatchContentType && mediaType != "application/json") {
+		w.Header().Set("Accept-Patch", mergePatchContentType)
+		writeProblem(w, r, http.StatusUnsupportedMediaType, "Content-Type must be "+mergePatchContentType)
+		return false
+	}
+
+	var patch map[string]json.RawMessage
+	if !decodeJSON(w, r, &patch) {
+		return false
+	}
+	if patch == nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid JSON")
+		return false
+	}
+
+	names := make([]string, 0, len(patch))
+	for name := range patch {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	v := validation.New()
+	for _, name := range names {
+		raw := patch[name]
+		dest, ok := fields[name]
+		switch {
+		case !ok:
+			v.Add(name, "is not a field of this resource")
+		case bytes.Equal(raw, []byte("null")):
+			v.Add(name, "cannot be removed")
+		default:
+			if err := json.Unmarshal(raw, dest); err != nil {
+				v.Add(name, "has an invalid value")
+			}
+		}
+	}
+	if err := v.Err(); err != nil {
+		writeError(w, r, err)
+		return false
+	}
+
+	return true
+}
@@ -1,59 +1,134 @@
 package controller
 
 import (
+	"HotelService/api/rest/middleware"
 	"HotelService/application/service"
-	"HotelService/infrastructure/db"
-	"database/sql"
+	"HotelService/domain/model"
+	"HotelService/infrastructure/auth"
+	"log/slog"
 	"net/http"
-	"strings"
+	"time"
 )
 
-func SetupRoutes(conn *sql.DB) *http.ServeMux {
-	mux := http.NewServeMux()
+// Defaults for the Options left zero
+const (
+	// DefaultTokenTTL is how long a hotelier login stays valid
+	DefaultTokenTTL = 24 * time.Hour
 
-	hotelRepo := db.NewHotelRepository(conn)
-	roomRepo := db.NewRoomRepository(conn)
+	// DefaultRequestTimeout is the deadline each request gets to finish its work
+	DefaultRequestTimeout = 30 * time.Second
 
-	hotelService := service.NewHotelService(hotelRepo, roomRepo)
+	// DefaultMaxRequestBodySize bounds request bodies; a hotel created with all
+	// its rooms is the largest legitimate one
+	DefaultMaxRequestBodySize = 1 << 20
+)
+
+// Options tunes the API
+type Options struct {
+	// AuthSecret signs the hotelier access tokens
+	AuthSecret         []byte
+	TokenTTL           time.Duration
+	RequestTimeout     time.Duration
+	MaxRequestBodySize int64
+
+	// Database and Schema are checked by /readyz; nil for in-memory storage
+	Database Pinger
+	Schema   SchemaVersioner
+
+	// Build is reported by /version
+	Build BuildInfo
+
+	// Draining is closed when graceful shutdown begins, which fails /readyz
+	Draining <-chan struct{}
+}
+
+// SetupRoutes wires the API on top of one storage backend
+func SetupRoutes(repos service.Repositories, opts Options) http.Handler {
+	if opts.TokenTTL == 0 {
+		opts.TokenTTL = DefaultTokenTTL
+	}
+	if opts.RequestTimeout == 0 {
+		opts.RequestTimeout = DefaultRequestTimeout
+	}
+	if opts.MaxRequestBodySize == 0 {
+		opts.MaxRequestBodySize = DefaultMaxRequestBodySize
+	}
+
+	router := NewRouter()
+
+	hotelService := service.NewHotelService(repos.UnitOfWork, repos.Hotels, repos.Rooms, repos.Reservations, repos.RatePlans, repos.Staff, repos.Audit)
+	authService := service.NewAuthService(repos.Hoteliers, auth.NewBcryptHasher(), auth.NewJWTIssuer(opts.AuthSecret, opts.TokenTTL))
+	auditService := service.NewAuditService(repos.Hotels, repos.Staff, repos.Audit)
+	accessService := service.NewAccessService(repos.UnitOfWork, repos.Hotels, repos.Rooms, repos.RatePlans, repos.Hoteliers, repos.Staff, repos.Audit)
+	idempotencyService := service.NewIdempotencyService(repos.Idempotency)
 
 	hotelierCtrl := NewHotelierController(hotelService)
 	clientCtrl := NewClientController(hotelService)
+	authCtrl := NewAuthController(authService)
+	staffCtrl := NewStaffController(accessService)
+	auditCtrl := NewAuditController(auditService)
+	healthCtrl := NewHealthController(opts.Database, opts.Schema, opts.Build, opts.Draining)
+	requireHotelier := authCtrl.RequireHotelier
+
+	// Creates honour Idempotency-Key so that retries cannot create duplicates
+	idempotency := idempotent(idempotencyService)
+
+	// Every hotelier route below, except creating and restoring a hotel, runs
+	// only if the caller's role at the hotel grants the permission it is
+	// guarded with
+	guard := NewGuard(accessService)
+
+	// Probe routes
+	router.Handle(http.MethodGet, "/healthz", healthCtrl.Health)
+	router.Handle(http.MethodGet, "/readyz", healthCtrl.Ready)
+	router.Handle(http.MethodGet, "/version", healthCtrl.Version)
+
+	// Hotelier account routes
+	router.Handle(http.MethodPost, "/hotelier/register", authCtrl.Register)
+	router.Handle(http.MethodPost, "/hotelier/login", authCtrl.Login)
 
 	// Hotelier routes
-	mux.HandleFunc("/hotelier/hotels", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPost {
-			hotelierCtrl.CreateHotel(w, r)
-		} else {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-	mux.HandleFunc("/hotelier/hotels/", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			hotelierCtrl.GetHotel(w, r)
-		} else if r.Method == http.MethodPut {
-			hotelierCtrl.UpdateHotel(w, r)
-		} else if strings.Contains(r.URL.Path, "/rooms") && r.Method == http.MethodPost {
-			hotelierCtrl.AddRoom(w, r)
-		} else {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-	mux.HandleFunc("/hotelier/rooms/", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPut {
-			hotelierCtrl.UpdateRoom(w, r)
-		} else if r.Method == http.MethodDelete {
-			hotelierCtrl.DeleteRoom(w, r)
-		} else if r.Method == http.MethodPatch && strings.Contains(r.URL.Path, "/availability") {
-			hotelierCtrl.UpdateRoomAvailability(w, r)
-		} else {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
+	router.Handle(http.MethodPost, "/hotelier/hotels", hotelierCtrl.CreateHotel, requireHotelier, idempotency)
+	router.Handle(http.MethodGet, "/hotelier/hotels/{id}", hotelierCtrl.GetHotel, requireHotelier, guard.Hotel(model.PermViewHotel))
+	router.Handle(http.MethodPut, "/hotelier/hotels/{id}", hotelierCtrl.UpdateHotel, requireHotelier, guard.Hotel(model.PermUpdateHotel))
+	router.Handle(http.MethodPatch, "/hotelier/hotels/{id}", hotelierCtrl.PatchHotel, requireHotelier, guard.Hotel(model.PermUpdateHotel))
+	router.Handle(http.MethodDelete, "/hotelier/hotels/{id}", hotelierCtrl.DeleteHotel, requireHotelier, guard.Hotel(model.PermDeleteHotel))
+	// A deleted hotel is invisible to the guard; the service authorizes restoring it
+	router.Handle(http.MethodPost, "/hotelier/hotels/{id}/restore", hotelierCtrl.RestoreHotel, requireHotelier)
+	router.Handle(http.MethodGet, "/hotelier/hotels/{id}/audit", auditCtrl.ListAuditLog, requireHotelier, guard.Hotel(model.PermViewAudit))
+	router.Handle(http.MethodPost, "/hotelier/hotels/{id}/rooms", hotelierCtrl.AddRoom, requireHotelier, guard.Hotel(model.PermManageRooms), idempotency)
+	router.Handle(http.MethodPost, "/hotelier/hotels/{id}/staff", staffCtrl.AssignStaff, requireHotelier, guard.Hotel(model.PermManageStaff))
+	router.Handle(http.MethodGet, "/hotelier/hotels/{id}/staff", staffCtrl.ListStaff, requireHotelier, guard.Hotel(model.PermManageStaff))
+	router.Handle(http.MethodDelete, "/hotelier/hotels/{id}/staff/{hotelierId}", staffCtrl.RemoveStaff, requireHotelier, guard.Hotel(model.PermManageStaff))
+	router.Handle(http.MethodPost, "/hotelier/hotels/{id}/rate-plans", hotelierCtrl.CreateRatePlan, requireHotelier, guard.Hotel(model.PermManageRatePlans))
+	router.Handle(http.MethodGet, "/hotelier/hotels/{id}/rate-plans", hotelierCtrl.ListRatePlans, requireHotelier, guard.Hotel(model.PermViewRatePlans))
+
+	router.Handle(http.MethodGet, "/hotelier/rooms/{id}", hotelierCtrl.GetRoom, requireHotelier, guard.Room(model.PermViewHotel))
+	router.Handle(http.MethodPut, "/hotelier/rooms/{id}", hotelierCtrl.UpdateRoom, requireHotelier, guard.Room(model.PermUpdateRooms))
+	router.Handle(http.MethodPatch, "/hotelier/rooms/{id}", hotelierCtrl.PatchRoom, requireHotelier, guard.Room(model.PermUpdateRooms))
+	router.Handle(http.MethodDelete, "/hotelier/rooms/{id}", hotelierCtrl.DeleteRoom, requireHotelier, guard.Room(model.PermManageRooms))
+	router.Handle(http.MethodPatch, "/hotelier/rooms/{id}/availability", hotelierCtrl.UpdateRoomAvailability, requireHotelier, guard.Room(model.PermSetAvailability))
+	router.Handle(http.MethodGet, "/hotelier/rooms/{id}/reservations", hotelierCtrl.ListRoomReservations, requireHotelier, guard.Room(model.PermViewReservations))
+
+	router.Handle(http.MethodGet, "/hotelier/rate-plans/{id}", hotelierCtrl.GetRatePlan, requireHotelier, guard.RatePlan(model.PermViewRatePlans))
+	router.Handle(http.MethodPost, "/hotelier/rate-plans/{id}/seasons", hotelierCtrl.AddSeasonalRate, requireHotelier, guard.RatePlan(model.PermManageRatePlans))
+	router.Handle(http.MethodPut, "/hotelier/rate-plans/{id}/day-modifiers", hotelierCtrl.SetDayModifier, requireHotelier, guard.RatePlan(model.PermManageRatePlans))
 
 	// Client routes
-	mux.HandleFunc("/client/hotels", clientCtrl.ListHotels)
-	mux.HandleFunc("/client/hotels/", clientCtrl.GetHotelDetails)
-	mux.HandleFunc("/client/rooms/available", clientCtrl.FindAvailableRooms)
+	router.Handle(http.MethodGet, "/client/hotels", clientCtrl.ListHotels)
+	router.Handle(http.MethodGet, "/client/hotels/{id}", clientCtrl.GetHotelDetails)
+	router.Handle(http.MethodGet, "/client/rooms/available", clientCtrl.FindAvailableRooms)
+	router.Handle(http.MethodGet, "/client/rooms/{id}/quote", clientCtrl.QuoteStay)
+	router.Handle(http.MethodPost, "/client/reservations", clientCtrl.CreateReservation)
+	router.Handle(http.MethodGet, "/client/reservations/{id}", clientCtrl.GetReservation)
+	router.Handle(http.MethodDelete, "/client/reservations/{id}", clientCtrl.CancelReservation)
 
-	return mux
+	logger := slog.Default()
+	return middleware.Chain(router,
+		middleware.RequestID,
+		middleware.Logger(logger),
+		middleware.Recover(logger, writeProblem),
+		middleware.Timeout(opts.RequestTimeout),
+		middleware.MaxBodySize(opts.MaxRequestBodySize),
+	)
 }
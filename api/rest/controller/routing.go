@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"HotelService/api/rest/middleware"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Router registers routes as Go 1.22 ServeMux patterns and answers the requests
+// no route matches with problem responses: 404 for unknown paths, and 405 with
+// an Allow header for known paths requested with another method.
+type Router struct {
+	mux     *http.ServeMux
+	methods map[string][]string
+}
+
+func NewRouter() *Router {
+	r := &Router{
+		mux:     http.NewServeMux(),
+		methods: make(map[string][]string),
+	}
+	r.mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		writeProblem(w, req, http.StatusNotFound, "Not found")
+	})
+	return r
+}
+
+// Handle routes method requests for pattern, e.g. "/hotelier/hotels/{id}", to
+// handler wrapped in route middleware such as authentication or a permission
+// guard, which runs inside the middleware every request goes through
+func (r *Router) Handle(method, pattern string, handler http.HandlerFunc, routeMiddleware ...middleware.Middleware) {
+	if _, ok := r.methods[pattern]; !ok {
+		r.mux.HandleFunc(pattern, func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Allow", r.allow(pattern))
+			writeProblem(w, req, http.StatusMethodNotAllowed, "Method not allowed")
+		})
+	}
+	r.methods[pattern] = append(r.methods[pattern], method)
+
+	r.mux.Handle(method+" "+pattern, middleware.Chain(handler, routeMiddleware...))
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mux.ServeHTTP(w, req)
+}
+
+// allow lists the methods of pattern; GET routes also answer HEAD
+func (r *Router) allow(pattern string) string {
+	methods := append([]string(nil), r.methods[pattern]...)
+	for _, method := range methods {
+		if method == http.MethodGet {
+			methods = append(methods, http.MethodHead)
+			break
+		}
+	}
+	sort.Strings(methods)
+	return strings.Join(methods, ", ")
+}
+
+// pathID reads the path parameter name as a positive ID
+func pathID(r *http.Request, name string) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
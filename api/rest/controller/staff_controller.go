@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"HotelService/application/service"
+	"HotelService/domain/model"
+	"encoding/json"
+	"net/http"
+)
+
+type StaffController struct {
+	accessService service.AccessService
+}
+
+func NewStaffController(accessService service.AccessService) *StaffController {
+	return &StaffController{
+		accessService: accessService,
+	}
+}
+
+type AssignStaffRequest struct {
+	Email string     `json:"email"`
+	Role  model.Role `json:"role"`
+}
+
+// AssignStaff POST /hotelier/hotels/{id}/staff
+func (c *StaffController) AssignStaff(w http.ResponseWriter, r *http.Request) {
+	hotelID, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
+		return
+	}
+
+	var req AssignStaffRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	member, err := c.accessService.AssignStaff(r.Context(), hotelID, req.Email, req.Role)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(member)
+}
+
+// ListStaff GET /hotelier/hotels/{id}/staff
+func (c *StaffController) ListStaff(w http.ResponseWriter, r *http.Request) {
+	hotelID, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
+		return
+	}
+
+	members, err := c.accessService.ListStaff(r.Context(), hotelID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(members)
+}
+
+// RemoveStaff DELETE /hotelier/hotels/{id}/staff/{hotelierId}
+func (c *StaffController) RemoveStaff(w http.ResponseWriter, r *http.Request) {
+	hotelID, ok := pathID(r, "id")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotel ID")
+		return
+	}
+
+	hotelierID, ok := pathID(r, "hotelierId")
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid hotelier ID")
+		return
+	}
+
+	if err := c.accessService.RemoveStaff(r.Context(), hotelID, hotelierID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
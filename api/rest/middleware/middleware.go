@@ -0,0 +1,59 @@
+// Package middleware holds the HTTP middleware every request goes through,
+// whatever route it ends up at
+package middleware
+
+import (
+	"net/http"
+)
+
+// Middleware wraps a handler with behaviour of its own. The middleware in this
+// package runs for every request; the API applies its route middleware, such
+// as authentication and permission guards, through the same type.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies middleware so that the first one listed runs first
+func Chain(handler http.Handler, middleware ...Middleware) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// ProblemWriter answers a request with a problem details body. The API passes
+// its own so that middleware errors look like every other error.
+type ProblemWriter func(w http.ResponseWriter, r *http.Request, status int, detail string)
+
+// responseRecorder remembers the status and size of the response passing through
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseRecorder) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover turns a panicking handler into a logged 500 problem response, as long
+// as the handler had not started its response yet
+func Recover(logger *slog.Logger, problem ProblemWriter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder := &responseRecorder{ResponseWriter: w}
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				if p == http.ErrAbortHandler {
+					// Deliberate aborts are left to net/http
+					panic(p)
+				}
+
+				logger.LogAttrs(r.Context(), slog.LevelError, "panic serving request",
+					slog.String("request_id", RequestIDFromContext(r.Context())),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.String("panic", fmt.Sprint(p)),
+					slog.String("stack", string(debug.Stack())),
+				)
+				if recorder.status == 0 {
+					problem(recorder, r, http.StatusInternalServerError, "An unexpected error occurred")
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
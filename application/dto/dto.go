@@ -1,8 +1,109 @@
 package dto
 
+import (
+	"HotelService/domain/model"
+	"time"
+)
+
 type RoomInput struct {
 	Number    string
 	Type      string
-	Price     float64
+	Price     model.Money
+	Capacity  int
 	Available bool
 }
+
+// HotelPatch holds the fields of a partial hotel update; nil fields are left unchanged
+type HotelPatch struct {
+	Name    *string
+	Address *string
+}
+
+// RoomPatch holds the fields of a partial room update; nil fields are left unchanged
+type RoomPatch struct {
+	Number    *string
+	Type      *string
+	Price     *model.Money
+	Capacity  *int
+	Available *bool
+}
+
+// SeasonalRateInput targets one room (RoomID), a room type (RoomType) or, with
+// neither set, every room of the hotel
+type SeasonalRateInput struct {
+	RoomID    *int64
+	RoomType  string
+	StartDate time.Time
+	EndDate   time.Time
+	Price     model.Money
+}
+
+// ReservationAccess is what a guest presents to reach a booking: the code they
+// were given when booking it, or the email they booked with
+type ReservationAccess struct {
+	Code       string
+	GuestEmail string
+}
+
+type AuthToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// PageRequest asks for one page of a listing. Cursor is the NextCursor of the previous page.
+type PageRequest struct {
+	Sort   string
+	Limit  int
+	Cursor string
+}
+
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPage returns a page of items; an empty page has an empty list of items,
+// never null, so that clients can always iterate it
+func NewPage[T any](items []T, nextCursor string) *Page[T] {
+	if items == nil {
+		items = make([]T, 0)
+	}
+	return &Page[T]{Items: items, NextCursor: nextCursor}
+}
+
+// HotelFilter selects hotels whose name and address contain the given substrings
+type HotelFilter struct {
+	Name    string
+	Address string
+	PageRequest
+}
+
+// StaySearch narrows available rooms down to a stay; zero values mean "any"
+type StaySearch struct {
+	CheckIn  time.Time
+	CheckOut time.Time
+	Guests   int
+	HotelID  int64
+	Type     string
+	MinPrice *model.Money
+	MaxPrice *model.Money
+	PageRequest
+}
+
+// AuditFilter selects the audit entries of one hotel, newest first; zero values
+// mean "any". Since is inclusive and Until exclusive.
+type AuditFilter struct {
+	HotelID    int64
+	ActorID    int64
+	Action     model.AuditAction
+	EntityType model.AuditEntity
+	EntityID   int64
+	Since      time.Time
+	Until      time.Time
+	PageRequest
+}
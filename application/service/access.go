@@ -0,0 +1,305 @@
+package service
+
+import (
+	"HotelService/application/validation"
+	"HotelService/domain/model"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type StaffRepository interface {
+	// Save assigns the member's role, replacing any role they already hold at the hotel
+	Save(ctx context.Context, member *model.StaffMember) error
+	Delete(ctx context.Context, hotelID, hotelierID int64) error
+	FindByHotelID(ctx context.Context, hotelID int64) ([]*model.StaffMember, error)
+	FindRole(ctx context.Context, hotelID, hotelierID int64) (model.Role, error)
+	RoleHasPermission(ctx context.Context, role model.Role, permission model.Permission) (bool, error)
+}
+
+// AccessService answers whether the hotelier in ctx may act on a hotel and
+// manages the staff roles that decide it
+type AccessService interface {
+	AuthorizeHotel(ctx context.Context, hotelID int64, permission model.Permission) error
+	AuthorizeRoom(ctx context.Context, roomID int64, permission model.Permission) error
+	AuthorizeRatePlan(ctx context.Context, ratePlanID int64, permission model.Permission) error
+
+	AssignStaff(ctx context.Context, hotelID int64, email string, role model.Role) (*model.StaffMember, error)
+	ListStaff(ctx context.Context, hotelID int64) ([]*model.StaffMember, error)
+	RemoveStaff(ctx context.Context, hotelID, hotelierID int64) error
+}
+
+// accessControl resolves the role of the hotelier in ctx at a hotel and checks
+// it against the permissions stored for that role
+type accessControl struct {
+	hotelRepo HotelRepository
+	staffRepo StaffRepository
+}
+
+func (a accessControl) authorizeHotel(ctx context.Context, hotelID int64, permission model.Permission) (*model.Hotel, error) {
+	if _, ok := HotelierFromContext(ctx); !ok {
+		return nil, model.NewUnauthenticatedError("authentication required")
+	}
+
+	hotel, err := a.hotelRepo.FindByID(ctx, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("hotel not found: %w", err)
+	}
+
+	if err := a.authorize(ctx, hotel, permission); err != nil {
+		return nil, err
+	}
+
+	return hotel, nil
+}
+
+// authorize checks the permission of the hotelier in ctx at an already loaded
+// hotel, which may be soft-deleted
+func (a accessControl) authorize(ctx context.Context, hotel *model.Hotel, permission model.Permission) error {
+	hotelierID, ok := HotelierFromContext(ctx)
+	if !ok {
+		return model.NewUnauthenticatedError("authentication required")
+	}
+
+	role := model.RoleOwner
+	if hotel.OwnerID != hotelierID {
+		var err error
+		role, err = a.staffRepo.FindRole(ctx, hotel.ID, hotelierID)
+		if err != nil {
+			if errors.Is(err, model.ErrNotFound) {
+				return model.NewForbiddenError("you do not manage hotel %d", hotel.ID)
+			}
+			return fmt.Errorf("failed to find staff role: %w", err)
+		}
+	}
+
+	allowed, err := a.staffRepo.RoleHasPermission(ctx, role, permission)
+	if err != nil {
+		return fmt.Errorf("failed to check permission: %w", err)
+	}
+	if !allowed {
+		return model.NewForbiddenError("role %s does not grant %s", role, permission)
+	}
+
+	return nil
+}
+
+// authorizeHotel loads the hotel once the hotelier in ctx is allowed the permission there
+func (s *HotelServiceImpl) authorizeHotel(ctx context.Context, hotelID int64, permission model.Permission) (*model.Hotel, error) {
+	return s.access.authorizeHotel(ctx, hotelID, permission)
+}
+
+// authorizeRoom loads the room once the hotelier in ctx is allowed the permission at its hotel
+func (s *HotelServiceImpl) authorizeRoom(ctx context.Context, roomID int64, permission model.Permission) (*model.Room, error) {
+	room, err := s.roomRepo.FindByID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("room not found: %w", err)
+	}
+
+	if _, err := s.access.authorizeHotel(ctx, room.HotelID, permission); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+type AccessServiceImpl struct {
+	uow          UnitOfWork
+	access       accessControl
+	roomRepo     RoomRepository
+	ratePlanRepo RatePlanRepository
+	hotelierRepo HotelierRepository
+	staffRepo    StaffRepository
+	auditRepo    AuditRepository
+}
+
+// NewAccessService returns the access service. Every staff change is recorded
+// in auditRepo.
+func NewAccessService(uow UnitOfWork, hotelRepo HotelRepository, roomRepo RoomRepository, ratePlanRepo RatePlanRepository, hotelierRepo HotelierRepository, staffRepo StaffRepository, auditRepo AuditRepository) AccessService {
+	return &AccessServiceImpl{
+		uow:          uow,
+		access:       accessControl{hotelRepo: hotelRepo, staffRepo: staffRepo},
+		roomRepo:     roomRepo,
+		ratePlanRepo: ratePlanRepo,
+		hotelierRepo: hotelierRepo,
+		staffRepo:    staffRepo,
+		auditRepo:    auditRepo,
+	}
+}
+
+func (s *AccessServiceImpl) AuthorizeHotel(ctx context.Context, hotelID int64, permission model.Permission) error {
+	if hotelID <= 0 {
+		return model.NewValidationError("invalid hotel ID")
+	}
+
+	_, err := s.access.authorizeHotel(ctx, hotelID, permission)
+	return err
+}
+
+func (s *AccessServiceImpl) AuthorizeRoom(ctx context.Context, roomID int64, permission model.Permission) error {
+	if roomID <= 0 {
+		return model.NewValidationError("invalid room ID")
+	}
+
+	room, err := s.roomRepo.FindByID(ctx, roomID)
+	if err != nil {
+		return fmt.Errorf("room not found: %w", err)
+	}
+
+	_, err = s.access.authorizeHotel(ctx, room.HotelID, permission)
+	return err
+}
+
+func (s *AccessServiceImpl) AuthorizeRatePlan(ctx context.Context, ratePlanID int64, permission model.Permission) error {
+	if ratePlanID <= 0 {
+		return model.NewValidationError("invalid rate plan ID")
+	}
+
+	plan, err := s.ratePlanRepo.FindByID(ctx, ratePlanID)
+	if err != nil {
+		return fmt.Errorf("rate plan not found: %w", err)
+	}
+
+	_, err = s.access.authorizeHotel(ctx, plan.HotelID, permission)
+	return err
+}
+
+func (s *AccessServiceImpl) AssignStaff(ctx context.Context, hotelID int64, email string, role model.Role) (*model.StaffMember, error) {
+	if hotelID <= 0 {
+		return nil, model.NewValidationError("invalid hotel ID")
+	}
+
+	email = normalizeEmail(email)
+
+	v := validation.New()
+	v.Required("email", email)
+	if v.Required("role", string(role)) {
+		v.Check(model.IsStaffRole(role), "role", "must be one of "+joinRoles(model.StaffRoles))
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	var member *model.StaffMember
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		hotel, err := s.access.authorizeHotel(ctx, hotelID, model.PermManageStaff)
+		if err != nil {
+			return err
+		}
+
+		hotelier, err := s.hotelierRepo.FindByEmail(ctx, email)
+		if err != nil {
+			return fmt.Errorf("hotelier not found: %w", err)
+		}
+
+		if hotelier.ID == hotel.OwnerID {
+			v := validation.New()
+			v.Add("email", "belongs to the hotel owner")
+			return v.Err()
+		}
+
+		before, err := s.findStaffMember(ctx, hotelID, hotelier.ID)
+		if err != nil && !errors.Is(err, model.ErrNotFound) {
+			return err
+		}
+
+		member = &model.StaffMember{
+			HotelID:    hotelID,
+			HotelierID: hotelier.ID,
+			Email:      hotelier.Email,
+			Name:       hotelier.Name,
+			Role:       role,
+			CreatedAt:  time.Now(),
+		}
+
+		if err := s.staffRepo.Save(ctx, member); err != nil {
+			return fmt.Errorf("failed to assign staff role: %w", err)
+		}
+
+		if before != nil {
+			return s.audit(ctx, hotelID, model.AuditUpdate, model.AuditStaff, hotelier.ID, before, member)
+		}
+		return s.audit(ctx, hotelID, model.AuditCreate, model.AuditStaff, hotelier.ID, nil, member)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+func (s *AccessServiceImpl) ListStaff(ctx context.Context, hotelID int64) ([]*model.StaffMember, error) {
+	if hotelID <= 0 {
+		return nil, model.NewValidationError("invalid hotel ID")
+	}
+
+	if _, err := s.access.authorizeHotel(ctx, hotelID, model.PermManageStaff); err != nil {
+		return nil, err
+	}
+
+	members, err := s.staffRepo.FindByHotelID(ctx, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staff: %w", err)
+	}
+
+	return members, nil
+}
+
+func (s *AccessServiceImpl) RemoveStaff(ctx context.Context, hotelID, hotelierID int64) error {
+	if hotelID <= 0 {
+		return model.NewValidationError("invalid hotel ID")
+	}
+	if hotelierID <= 0 {
+		return model.NewValidationError("invalid hotelier ID")
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.access.authorizeHotel(ctx, hotelID, model.PermManageStaff); err != nil {
+			return err
+		}
+
+		member, err := s.findStaffMember(ctx, hotelID, hotelierID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.staffRepo.Delete(ctx, hotelID, hotelierID); err != nil {
+			return fmt.Errorf("failed to remove staff member: %w", err)
+		}
+
+		return s.audit(ctx, hotelID, model.AuditDelete, model.AuditStaff, hotelierID, member, nil)
+	})
+}
+
+// findStaffMember returns the role the hotelier holds at the hotel, or a
+// NotFound error if they are not on its staff
+func (s *AccessServiceImpl) findStaffMember(ctx context.Context, hotelID, hotelierID int64) (*model.StaffMember, error) {
+	members, err := s.staffRepo.FindByHotelID(ctx, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staff: %w", err)
+	}
+
+	for _, member := range members {
+		if member.HotelierID == hotelierID {
+			return member, nil
+		}
+	}
+
+	return nil, model.NewNotFoundError("hotelier %d is not on the staff of hotel %d", hotelierID, hotelID)
+}
+
+// audit records a staff change the hotelier in ctx made at the hotel; see
+// HotelServiceImpl.audit
+func (s *AccessServiceImpl) audit(ctx context.Context, hotelID int64, action model.AuditAction, entityType model.AuditEntity, entityID int64, before, after interface{}) error {
+	return recordAudit(ctx, s.auditRepo, hotelID, action, entityType, entityID, before, after)
+}
+
+func joinRoles(roles []model.Role) string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	return strings.Join(names, ", ")
+}
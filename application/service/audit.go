@@ -0,0 +1,179 @@
+package service
+
+import (
+	"HotelService/application/dto"
+	"HotelService/application/validation"
+	"HotelService/domain/model"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type AuditRepository interface {
+	Save(ctx context.Context, entry *model.AuditEntry) error
+	// FindPage returns one page of the entries matching the filter, newest
+	// first, plus the cursor of the next page
+	FindPage(ctx context.Context, filter dto.AuditFilter) ([]*model.AuditEntry, string, error)
+}
+
+// AuditService reads the audit log HotelService and AccessService write for
+// every change a hotelier makes at a hotel
+type AuditService interface {
+	ListAuditLog(ctx context.Context, filter dto.AuditFilter) (*dto.Page[*model.AuditEntry], error)
+}
+
+type AuditServiceImpl struct {
+	access    accessControl
+	auditRepo AuditRepository
+}
+
+func NewAuditService(hotelRepo HotelRepository, staffRepo StaffRepository, auditRepo AuditRepository) AuditService {
+	return &AuditServiceImpl{
+		access:    accessControl{hotelRepo: hotelRepo, staffRepo: staffRepo},
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *AuditServiceImpl) ListAuditLog(ctx context.Context, filter dto.AuditFilter) (*dto.Page[*model.AuditEntry], error) {
+	if filter.HotelID <= 0 {
+		return nil, model.NewValidationError("invalid hotel ID")
+	}
+
+	v := validation.New()
+	switch filter.Action {
+	case "", model.AuditCreate, model.AuditUpdate, model.AuditDelete, model.AuditRestore, model.AuditPurge:
+	default:
+		v.Add("action", "must be one of create, update, delete, restore or purge")
+	}
+	switch filter.EntityType {
+	case "", model.AuditHotel, model.AuditRoom, model.AuditRatePlan, model.AuditSeasonalRate, model.AuditStaff:
+	default:
+		v.Add("entity_type", "must be one of hotel, room, rate_plan, seasonal_rate or staff")
+	}
+	v.Check(filter.Sort == "", "sort", "is not supported; entries are listed newest first")
+	if !filter.Since.IsZero() && !filter.Until.IsZero() {
+		v.Check(filter.Until.After(filter.Since), "until", "must be after since")
+	}
+	validatePageRequest(v, &filter.PageRequest)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.access.authorizeHotel(ctx, filter.HotelID, model.PermViewAudit); err != nil {
+		return nil, err
+	}
+
+	entries, nextCursor, err := s.auditRepo.FindPage(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+
+	return dto.NewPage(entries, nextCursor), nil
+}
+
+// audit records a change the hotelier in ctx made at the hotel. before is nil
+// for created entities and after is nil for deleted ones; pass copies taken
+// before the entity was modified. Call it inside the unit of work of the change
+// so the entry is only kept if the change is.
+func (s *HotelServiceImpl) audit(ctx context.Context, hotelID int64, action model.AuditAction, entityType model.AuditEntity, entityID int64, before, after interface{}) error {
+	return recordAudit(ctx, s.auditRepo, hotelID, action, entityType, entityID, before, after)
+}
+
+// recordAudit saves the audit entry of a change by the hotelier in ctx in
+// auditRepo; see audit
+func recordAudit(ctx context.Context, auditRepo AuditRepository, hotelID int64, action model.AuditAction, entityType model.AuditEntity, entityID int64, before, after interface{}) error {
+	actorID, ok := HotelierFromContext(ctx)
+	if !ok {
+		return model.NewUnauthenticatedError("authentication required")
+	}
+
+	return saveAudit(ctx, auditRepo, actorID, hotelID, action, entityType, entityID, before, after)
+}
+
+// saveAudit saves the audit entry of a change by actorID, which is 0 for
+// maintenance jobs
+func saveAudit(ctx context.Context, auditRepo AuditRepository, actorID, hotelID int64, action model.AuditAction, entityType model.AuditEntity, entityID int64, before, after interface{}) error {
+	changes, err := diffFields(before, after)
+	if err != nil {
+		return fmt.Errorf("failed to diff %s %d: %w", entityType, entityID, err)
+	}
+	if action == model.AuditUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	entry := &model.AuditEntry{
+		HotelID:    hotelID,
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+		CreatedAt:  time.Now(),
+	}
+	if err := auditRepo.Save(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// unaudited fields change on every write or are audited as entities of their own
+var unaudited = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"version":    true,
+	"deleted_at": true,
+	"rooms":      true,
+}
+
+// diffFields compares the JSON encodings of before and after field by field
+// and returns the fields whose values differ
+func diffFields(before, after interface{}) (map[string]model.FieldChange, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]model.FieldChange)
+	for name, value := range beforeFields {
+		if !bytes.Equal(value, afterFields[name]) {
+			changes[name] = model.FieldChange{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = model.FieldChange{After: value}
+		}
+	}
+
+	return changes, nil
+}
+
+func jsonFields(v interface{}) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if v == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for name := range fields {
+		if unaudited[name] {
+			delete(fields, name)
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"HotelService/application/dto"
+	"HotelService/application/validation"
+	"HotelService/domain/model"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	MinPasswordLength = 8
+	// bcrypt ignores everything past 72 bytes
+	MaxPasswordLength = 72
+)
+
+type HotelierRepository interface {
+	Save(ctx context.Context, hotelier *model.Hotelier) error
+	FindByID(ctx context.Context, id int64) (*model.Hotelier, error)
+	FindByEmail(ctx context.Context, email string) (*model.Hotelier, error)
+}
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Compare(hash, password string) error
+}
+
+type TokenIssuer interface {
+	Issue(hotelierID int64) (string, time.Time, error)
+	Verify(token string) (int64, error)
+}
+
+type AuthService interface {
+	Register(ctx context.Context, email, password, name string) (*model.Hotelier, error)
+	Login(ctx context.Context, email, password string) (*dto.AuthToken, error)
+	Authenticate(ctx context.Context, token string) (*model.Hotelier, error)
+}
+
+type hotelierKey struct{}
+
+// WithHotelier marks ctx as acting on behalf of the hotelier
+func WithHotelier(ctx context.Context, hotelierID int64) context.Context {
+	return context.WithValue(ctx, hotelierKey{}, hotelierID)
+}
+
+// HotelierFromContext returns the hotelier the request acts on behalf of
+func HotelierFromContext(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(hotelierKey{}).(int64)
+	return id, ok
+}
+
+type AuthServiceImpl struct {
+	hotelierRepo HotelierRepository
+	hasher       PasswordHasher
+	tokens       TokenIssuer
+
+	// compared against when the email is unknown so both failure paths take as long
+	dummyHash string
+}
+
+func NewAuthService(hotelierRepo HotelierRepository, hasher PasswordHasher, tokens TokenIssuer) AuthService {
+	dummyHash, _ := hasher.Hash("not-a-real-password")
+	return &AuthServiceImpl{
+		hotelierRepo: hotelierRepo,
+		hasher:       hasher,
+		tokens:       tokens,
+		dummyHash:    dummyHash,
+	}
+}
+
+func (s *AuthServiceImpl) Register(ctx context.Context, email, password, name string) (*model.Hotelier, error) {
+	email = normalizeEmail(email)
+
+	v := validation.New()
+	if v.Required("email", email) {
+		v.MaxLength("email", email, model.MaxEmailLength)
+		v.Check(strings.Contains(email, "@"), "email", "must be a valid email address")
+	}
+	if v.Required("name", name) {
+		v.MaxLength("name", name, model.MaxHotelierNameLength)
+	}
+	v.Check(len(password) >= MinPasswordLength, "password", fmt.Sprintf("must be at least %d characters", MinPasswordLength))
+	v.Check(len(password) <= MaxPasswordLength, "password", fmt.Sprintf("must be at most %d bytes", MaxPasswordLength))
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	hotelier := &model.Hotelier{
+		Email:        email,
+		Name:         name,
+		PasswordHash: hash,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.hotelierRepo.Save(ctx, hotelier); err != nil {
+		return nil, fmt.Errorf("failed to register hotelier: %w", err)
+	}
+
+	return hotelier, nil
+}
+
+func (s *AuthServiceImpl) Login(ctx context.Context, email, password string) (*dto.AuthToken, error) {
+	invalid := model.NewUnauthenticatedError("invalid email or password")
+
+	hotelier, err := s.hotelierRepo.FindByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			s.hasher.Compare(s.dummyHash, password)
+			return nil, invalid
+		}
+		return nil, fmt.Errorf("failed to find hotelier: %w", err)
+	}
+
+	if err := s.hasher.Compare(hotelier.PasswordHash, password); err != nil {
+		return nil, invalid
+	}
+
+	token, expiresAt, err := s.tokens.Issue(hotelier.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AuthToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+func (s *AuthServiceImpl) Authenticate(ctx context.Context, token string) (*model.Hotelier, error) {
+	id, err := s.tokens.Verify(token)
+	if err != nil {
+		return nil, model.NewUnauthenticatedError("invalid or expired token")
+	}
+
+	hotelier, err := s.hotelierRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.NewUnauthenticatedError("account no longer exists")
+		}
+		return nil, fmt.Errorf("failed to load hotelier: %w", err)
+	}
+
+	return hotelier, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
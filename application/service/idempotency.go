@@ -0,0 +1,128 @@
+package service
+
+import (
+	"HotelService/domain/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// IdempotencyKeyTTL is how long a response is kept for replay; the purge job
+// forgets keys older than that
+const IdempotencyKeyTTL = 24 * time.Hour
+
+type IdempotencyRepository interface {
+	// Save stores a new request and fails with model.ErrConflict when the
+	// hotelier has already used its key
+	Save(ctx context.Context, request *model.IdempotentRequest) error
+	FindByKey(ctx context.Context, hotelierID int64, key string) (*model.IdempotentRequest, error)
+	Complete(ctx context.Context, request *model.IdempotentRequest) error
+	Delete(ctx context.Context, hotelierID int64, key string) error
+	PurgeBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// IdempotencyService lets hoteliers retry creates safely: a request sent with an
+// Idempotency-Key is processed once, and retries with the same key get the
+// original response
+type IdempotencyService interface {
+	// Begin claims the key of the hotelier in ctx for a request. It returns the
+	// earlier request to replay when the key has been used before, or nil when
+	// the caller should process the request and then Finish it.
+	Begin(ctx context.Context, key, requestHash string) (*model.IdempotentRequest, error)
+	// Finish stores the response to a request claimed with Begin. Server errors
+	// are not stored, so that a retry processes the request again.
+	Finish(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	// Release forgets a key claimed with Begin whose request failed before it
+	// could be finished, so that a retry processes the request again
+	Release(ctx context.Context, key string) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type IdempotencyServiceImpl struct {
+	repo IdempotencyRepository
+}
+
+func NewIdempotencyService(repo IdempotencyRepository) IdempotencyService {
+	return &IdempotencyServiceImpl{repo: repo}
+}
+
+func (s *IdempotencyServiceImpl) Begin(ctx context.Context, key, requestHash string) (*model.IdempotentRequest, error) {
+	hotelierID, ok := HotelierFromContext(ctx)
+	if !ok {
+		return nil, model.NewUnauthenticatedError("authentication required")
+	}
+	if key == "" || len(key) > model.MaxIdempotencyKeyLength {
+		return nil, model.NewValidationError("Idempotency-Key must be 1 to %d characters", model.MaxIdempotencyKeyLength)
+	}
+
+	request := &model.IdempotentRequest{HotelierID: hotelierID, Key: key, RequestHash: requestHash}
+	err := s.repo.Save(ctx, request)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, model.ErrConflict) {
+		return nil, fmt.Errorf("failed to save idempotency key: %w", err)
+	}
+
+	earlier, err := s.repo.FindByKey(ctx, hotelierID, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load idempotency key: %w", err)
+	}
+	if earlier.RequestHash != requestHash {
+		return nil, model.NewUnprocessableError("Idempotency-Key %q was already used for a different request", key)
+	}
+	if !earlier.Completed() {
+		return nil, model.NewConflictError("the request with Idempotency-Key %q is still being processed", key)
+	}
+
+	return earlier, nil
+}
+
+func (s *IdempotencyServiceImpl) Finish(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	hotelierID, ok := HotelierFromContext(ctx)
+	if !ok {
+		return model.NewUnauthenticatedError("authentication required")
+	}
+
+	if statusCode >= 500 {
+		return s.Release(ctx, key)
+	}
+
+	request := &model.IdempotentRequest{
+		HotelierID:  hotelierID,
+		Key:         key,
+		StatusCode:  statusCode,
+		ContentType: contentType,
+		Body:        body,
+	}
+	if err := s.repo.Complete(ctx, request); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+func (s *IdempotencyServiceImpl) Release(ctx context.Context, key string) error {
+	hotelierID, ok := HotelierFromContext(ctx)
+	if !ok {
+		return model.NewUnauthenticatedError("authentication required")
+	}
+
+	if err := s.repo.Delete(ctx, hotelierID, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeExpired forgets keys older than IdempotencyKeyTTL. It runs as a
+// maintenance job, not on behalf of a hotelier.
+func (s *IdempotencyServiceImpl) PurgeExpired(ctx context.Context) (int64, error) {
+	purged, err := s.repo.PurgeBefore(ctx, time.Now().Add(-IdempotencyKeyTTL))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+
+	return purged, nil
+}
@@ -4,16 +4,47 @@ import (
 	"HotelService/application/dto"
 	"HotelService/domain/model"
 	"context"
+	"time"
 )
 
+// UnitOfWork runs fn as a single all-or-nothing unit. Repository calls made with the
+// context passed to fn take part in the same transaction.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// HotelRepository stores hotels. Soft-deleted hotels and their rooms are
+// invisible to every method of it and of RoomRepository except FindDeletedByID,
+// Restore and PurgeDeleted. Restore brings back the rooms deleted with the
+// hotel, not those deleted before it.
+//
+// Hotels and rooms carry a version that every change bumps. Update,
+// UpdateAvailability, SoftDelete and the room's Delete only apply to the
+// version they are given and fail with model.ErrPreconditionFailed once someone
+// else has changed the row. The deletes take AnyVersion to skip the check.
+// BumpVersion changes the version of a hotel whose rooms changed, since its
+// rooms are part of it and of its ETag.
 type HotelRepository interface {
 	Save(ctx context.Context, hotel *model.Hotel) error
 	Update(ctx context.Context, hotel *model.Hotel) error
 	FindByID(ctx context.Context, id int64) (*model.Hotel, error)
 	FindAll(ctx context.Context) ([]*model.Hotel, error)
+	FindPage(ctx context.Context, filter dto.HotelFilter) ([]*model.Hotel, string, error)
 	Delete(ctx context.Context, id int64) error
+	SoftDelete(ctx context.Context, id, version int64) error
+	FindDeletedByID(ctx context.Context, id int64) (*model.Hotel, error)
+	Restore(ctx context.Context, id int64) error
+	BumpVersion(ctx context.Context, id int64) error
+	// PurgeDeleted removes hotels soft-deleted before the cutoff for good with
+	// their cancelled and past reservations, and returns the removed hotels
+	// without their rooms. A hotel with a confirmed stay yet to end is kept.
+	PurgeDeleted(ctx context.Context, before time.Time) ([]*model.Hotel, error)
 }
 
+// RoomRepository stores rooms. Delete soft-deletes the room so that its
+// reservations stay on record, and frees its number for a new room. It fails
+// with model.ErrConflict while the room has a confirmed reservation that has
+// not ended.
 type RoomRepository interface {
 	Save(ctx context.Context, room *model.Room) error
 	Update(ctx context.Context, room *model.Room) error
@@ -21,18 +52,69 @@ type RoomRepository interface {
 	FindAll(ctx context.Context) ([]*model.Room, error)
 	FindByHotelID(ctx context.Context, hotelID int64) ([]*model.Room, error)
 	FindAllAvailable(ctx context.Context) ([]*model.Room, error)
-	Delete(ctx context.Context, id int64) error
-	UpdateAvailability(ctx context.Context, id int64, available bool) error
+	FindAvailableForStay(ctx context.Context, search dto.StaySearch) ([]*model.Room, string, error)
+	Delete(ctx context.Context, id, version int64) error
+	UpdateAvailability(ctx context.Context, id int64, available bool, version int64) error
+}
+
+type ReservationRepository interface {
+	Save(ctx context.Context, reservation *model.Reservation) error
+	FindByID(ctx context.Context, id int64) (*model.Reservation, error)
+	FindByCode(ctx context.Context, code string) (*model.Reservation, error)
+	FindByRoomID(ctx context.Context, roomID int64) ([]*model.Reservation, error)
+	HasOverlap(ctx context.Context, roomID int64, checkIn, checkOut time.Time) (bool, error)
+	// HasUpcomingStays reports whether a room of the hotel has a confirmed
+	// reservation that has not ended
+	HasUpcomingStays(ctx context.Context, hotelID int64) (bool, error)
+	UpdateStatus(ctx context.Context, id int64, status model.ReservationStatus) error
+}
+
+type RatePlanRepository interface {
+	Save(ctx context.Context, plan *model.RatePlan) error
+	FindByID(ctx context.Context, id int64) (*model.RatePlan, error)
+	FindByHotelID(ctx context.Context, hotelID int64) ([]*model.RatePlan, error)
+	SaveSeasonalRate(ctx context.Context, rate *model.SeasonalRate) error
+	SetDayModifier(ctx context.Context, ratePlanID int64, modifier model.DayModifier) error
+}
+
+// Repositories bundles the storage of one backend. Every repository in it works
+// with the UnitOfWork of the same backend.
+type Repositories struct {
+	UnitOfWork   UnitOfWork
+	Hotels       HotelRepository
+	Rooms        RoomRepository
+	Reservations ReservationRepository
+	RatePlans    RatePlanRepository
+	Hoteliers    HotelierRepository
+	Staff        StaffRepository
+	Audit        AuditRepository
+	Idempotency  IdempotencyRepository
 }
 
 type HotelService interface {
-	CreateHotel(ctx context.Context, name, address string, rooms []dto.RoomInput) (*model.Hotel, error)
+	CreateHotel(ctx context.Context, name, address, currency string, rooms []dto.RoomInput) (*model.Hotel, error)
 	GetHotel(ctx context.Context, id int64) (*model.Hotel, error)
-	ListHotels(ctx context.Context) ([]*model.Hotel, error)
-	UpdateRoomAvailability(ctx context.Context, roomID int64, available bool) error
-	FindAvailableRooms(ctx context.Context) ([]*model.Room, error)
-	UpdateHotel(ctx context.Context, id int64, name, address string) (*model.Hotel, error)
-	AddRoomToHotel(ctx context.Context, hotelID int64, number, roomType string, price float64, available bool) (*model.Room, error)
-	UpdateRoom(ctx context.Context, id int64, number, roomType string, price float64, available bool) (*model.Room, error)
-	DeleteRoom(ctx context.Context, id int64) error
+	ListHotels(ctx context.Context, filter dto.HotelFilter) (*dto.Page[*model.Hotel], error)
+	UpdateRoomAvailability(ctx context.Context, roomID, version int64, available bool) (*model.Room, error)
+	FindAvailableRooms(ctx context.Context, search dto.StaySearch) (*dto.Page[*model.Room], error)
+	UpdateHotel(ctx context.Context, id, version int64, name, address string) (*model.Hotel, error)
+	PatchHotel(ctx context.Context, id, version int64, patch dto.HotelPatch) (*model.Hotel, error)
+	DeleteHotel(ctx context.Context, id, version int64) error
+	RestoreHotel(ctx context.Context, id int64) (*model.Hotel, error)
+	PurgeDeletedHotels(ctx context.Context, retention time.Duration) (int64, error)
+	AddRoomToHotel(ctx context.Context, hotelID int64, number, roomType string, price model.Money, capacity int, available bool) (*model.Room, error)
+	GetRoom(ctx context.Context, id int64) (*model.Room, error)
+	UpdateRoom(ctx context.Context, id, version int64, number, roomType string, price model.Money, capacity int, available bool) (*model.Room, error)
+	PatchRoom(ctx context.Context, id, version int64, patch dto.RoomPatch) (*model.Room, error)
+	DeleteRoom(ctx context.Context, id, version int64) error
+	CreateReservation(ctx context.Context, roomID int64, guestName, guestEmail string, checkIn, checkOut time.Time) (*model.Reservation, error)
+	GetReservation(ctx context.Context, id int64, access dto.ReservationAccess) (*model.Reservation, error)
+	ListRoomReservations(ctx context.Context, roomID int64) ([]*model.Reservation, error)
+	CancelReservation(ctx context.Context, id int64, access dto.ReservationAccess) error
+	CreateRatePlan(ctx context.Context, hotelID int64, name string) (*model.RatePlan, error)
+	GetRatePlan(ctx context.Context, id int64) (*model.RatePlan, error)
+	ListRatePlans(ctx context.Context, hotelID int64) ([]*model.RatePlan, error)
+	AddSeasonalRate(ctx context.Context, ratePlanID int64, input dto.SeasonalRateInput) (*model.SeasonalRate, error)
+	SetDayModifier(ctx context.Context, ratePlanID int64, weekday time.Weekday, multiplier float64) error
+	QuoteStay(ctx context.Context, roomID, ratePlanID int64, checkIn, checkOut time.Time) (*model.PriceQuote, error)
 }
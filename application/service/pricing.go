@@ -0,0 +1,250 @@
+package service
+
+import (
+	"HotelService/application/dto"
+	"HotelService/application/validation"
+	"HotelService/domain/model"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxQuoteNights bounds the length of a priced stay
+const MaxQuoteNights = 365
+
+func (s *HotelServiceImpl) CreateRatePlan(ctx context.Context, hotelID int64, name string) (*model.RatePlan, error) {
+	if hotelID <= 0 {
+		return nil, model.NewValidationError("invalid hotel ID")
+	}
+
+	v := validation.New()
+	if v.Required("name", name) {
+		v.MaxLength("name", name, model.MaxRatePlanNameLength)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	plan := &model.RatePlan{
+		HotelID:   hotelID,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.authorizeHotel(ctx, hotelID, model.PermManageRatePlans); err != nil {
+			return err
+		}
+
+		if err := s.ratePlanRepo.Save(ctx, plan); err != nil {
+			return fmt.Errorf("failed to create rate plan: %w", err)
+		}
+
+		return s.audit(ctx, hotelID, model.AuditCreate, model.AuditRatePlan, plan.ID, nil, plan)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (s *HotelServiceImpl) GetRatePlan(ctx context.Context, id int64) (*model.RatePlan, error) {
+	if id <= 0 {
+		return nil, model.NewValidationError("invalid rate plan ID")
+	}
+
+	plan, err := s.ratePlanRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate plan: %w", err)
+	}
+
+	if _, err := s.authorizeHotel(ctx, plan.HotelID, model.PermViewRatePlans); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (s *HotelServiceImpl) ListRatePlans(ctx context.Context, hotelID int64) ([]*model.RatePlan, error) {
+	if hotelID <= 0 {
+		return nil, model.NewValidationError("invalid hotel ID")
+	}
+
+	if _, err := s.authorizeHotel(ctx, hotelID, model.PermViewRatePlans); err != nil {
+		return nil, err
+	}
+
+	plans, err := s.ratePlanRepo.FindByHotelID(ctx, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rate plans: %w", err)
+	}
+
+	return plans, nil
+}
+
+func (s *HotelServiceImpl) AddSeasonalRate(ctx context.Context, ratePlanID int64, input dto.SeasonalRateInput) (*model.SeasonalRate, error) {
+	if ratePlanID <= 0 {
+		return nil, model.NewValidationError("invalid rate plan ID")
+	}
+
+	startDate := truncateToDate(input.StartDate)
+	endDate := truncateToDate(input.EndDate)
+
+	v := validation.New()
+	v.Check(!input.StartDate.IsZero(), "start_date", "is required")
+	v.Check(!input.EndDate.IsZero(), "end_date", "is required")
+	v.Check(endDate.After(startDate), "end_date", "must be after start_date")
+	v.Check(input.RoomID == nil || input.RoomType == "", "room_type", "must not be set together with room_id")
+	v.MaxLength("room_type", input.RoomType, model.MaxRoomTypeLength)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	rate := &model.SeasonalRate{
+		RatePlanID: ratePlanID,
+		RoomID:     input.RoomID,
+		RoomType:   input.RoomType,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Price:      input.Price,
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		plan, err := s.ratePlanRepo.FindByID(ctx, ratePlanID)
+		if err != nil {
+			return fmt.Errorf("rate plan not found: %w", err)
+		}
+
+		hotel, err := s.authorizeHotel(ctx, plan.HotelID, model.PermManageRatePlans)
+		if err != nil {
+			return err
+		}
+
+		v := validation.New()
+		v.Price("price", input.Price, hotel.Currency)
+		if err := v.Err(); err != nil {
+			return err
+		}
+
+		if input.RoomID != nil {
+			room, err := s.roomRepo.FindByID(ctx, *input.RoomID)
+			if err != nil {
+				return fmt.Errorf("room not found: %w", err)
+			}
+			if room.HotelID != plan.HotelID {
+				return model.NewValidationError("room %d does not belong to hotel %d", room.ID, plan.HotelID)
+			}
+		}
+
+		if err := s.ratePlanRepo.SaveSeasonalRate(ctx, rate); err != nil {
+			return fmt.Errorf("failed to add seasonal rate: %w", err)
+		}
+
+		return s.audit(ctx, plan.HotelID, model.AuditCreate, model.AuditSeasonalRate, rate.ID, nil, rate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s *HotelServiceImpl) SetDayModifier(ctx context.Context, ratePlanID int64, weekday time.Weekday, multiplier float64) error {
+	if ratePlanID <= 0 {
+		return model.NewValidationError("invalid rate plan ID")
+	}
+
+	// Every store keeps the multiplier to four decimal places; one with more is
+	// rejected rather than rounded, like an over-precise price
+	multiplier, inexact := model.RoundMultiplier(multiplier)
+
+	v := validation.New()
+	v.Check(weekday >= time.Sunday && weekday <= time.Saturday, "weekday", "must be between 0 (Sunday) and 6 (Saturday)")
+	v.Positive("multiplier", multiplier)
+	v.Check(multiplier < model.MaxMultiplier, "multiplier", fmt.Sprintf("must be less than %d", model.MaxMultiplier))
+	v.Check(!inexact, "multiplier", "must have at most 4 decimal places")
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		plan, err := s.ratePlanRepo.FindByID(ctx, ratePlanID)
+		if err != nil {
+			return fmt.Errorf("rate plan not found: %w", err)
+		}
+
+		if _, err := s.authorizeHotel(ctx, plan.HotelID, model.PermManageRatePlans); err != nil {
+			return err
+		}
+
+		if err := s.ratePlanRepo.SetDayModifier(ctx, ratePlanID, model.DayModifier{Weekday: weekday, Multiplier: multiplier}); err != nil {
+			return fmt.Errorf("failed to set day modifier: %w", err)
+		}
+
+		// Audited as a change of the plan's field for the weekday
+		field := "day_modifiers." + strings.ToLower(weekday.String())
+		before := map[string]float64{}
+		for _, modifier := range plan.DayModifiers {
+			if modifier.Weekday == weekday {
+				before[field] = modifier.Multiplier
+			}
+		}
+		after := map[string]float64{field: multiplier}
+		return s.audit(ctx, plan.HotelID, model.AuditUpdate, model.AuditRatePlan, ratePlanID, before, after)
+	})
+}
+
+// QuoteStay prices every night of the stay. Without a rate plan the room's own
+// price is charged for each night.
+func (s *HotelServiceImpl) QuoteStay(ctx context.Context, roomID, ratePlanID int64, checkIn, checkOut time.Time) (*model.PriceQuote, error) {
+	if roomID <= 0 {
+		return nil, model.NewValidationError("invalid room ID")
+	}
+
+	checkIn = truncateToDate(checkIn)
+	checkOut = truncateToDate(checkOut)
+
+	v := validation.New()
+	v.Check(checkOut.After(checkIn), "check_out", "must be after check_in")
+	v.Check(!checkOut.After(checkIn.AddDate(0, 0, MaxQuoteNights)), "check_out", fmt.Sprintf("stay must not exceed %d nights", MaxQuoteNights))
+	v.Check(ratePlanID >= 0, "rate_plan_id", "must not be negative")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	room, err := s.roomRepo.FindByID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("room not found: %w", err)
+	}
+
+	plan := &model.RatePlan{}
+	if ratePlanID > 0 {
+		if plan, err = s.ratePlanRepo.FindByID(ctx, ratePlanID); err != nil {
+			return nil, fmt.Errorf("rate plan not found: %w", err)
+		}
+		if plan.HotelID != room.HotelID {
+			return nil, model.NewValidationError("rate plan %d does not belong to hotel %d", plan.ID, room.HotelID)
+		}
+	}
+
+	quote := &model.PriceQuote{
+		RoomID:     roomID,
+		RatePlanID: ratePlanID,
+		CheckIn:    checkIn,
+		CheckOut:   checkOut,
+		Total:      model.NewMoney(0, room.Price.Currency),
+	}
+	for night := checkIn; night.Before(checkOut); night = night.AddDate(0, 0, 1) {
+		price := plan.NightlyPrice(room, night)
+		quote.Nights = append(quote.Nights, price)
+		if quote.Total, err = quote.Total.Add(price.Price); err != nil {
+			return nil, fmt.Errorf("failed to total stay price: %w", err)
+		}
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"HotelService/application/dto"
+	"HotelService/application/validation"
+	"HotelService/domain/model"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// roomAlreadyBooked is the conflict returned when another confirmed reservation
+// holds the room for part of the stay; it wraps model.ErrReservationOverlap
+func roomAlreadyBooked(roomID int64, checkIn, checkOut time.Time) error {
+	err := model.NewConflictError("room %d is already booked between %s and %s",
+		roomID, checkIn.Format("2006-01-02"), checkOut.Format("2006-01-02"))
+	err.Err = model.ErrReservationOverlap
+	return err
+}
+
+func (s *HotelServiceImpl) CreateReservation(ctx context.Context, roomID int64, guestName, guestEmail string, checkIn, checkOut time.Time) (*model.Reservation, error) {
+	if roomID <= 0 {
+		return nil, model.NewValidationError("invalid room ID")
+	}
+
+	checkIn = truncateToDate(checkIn)
+	checkOut = truncateToDate(checkOut)
+
+	v := validation.New()
+	if v.Required("guest_name", guestName) {
+		v.MaxLength("guest_name", guestName, model.MaxGuestNameLength)
+	}
+	if v.Required("guest_email", guestEmail) {
+		v.MaxLength("guest_email", guestEmail, model.MaxGuestEmailLength)
+	}
+	v.Check(checkOut.After(checkIn), "check_out", "must be after check_in")
+	v.Check(!checkIn.Before(truncateToDate(time.Now())), "check_in", "cannot be in the past")
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	code, err := newReservationCode()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reservation := &model.Reservation{
+		RoomID:     roomID,
+		Code:       code,
+		GuestName:  guestName,
+		GuestEmail: guestEmail,
+		CheckIn:    checkIn,
+		CheckOut:   checkOut,
+		Status:     model.ReservationStatusConfirmed,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		room, err := s.roomRepo.FindByID(ctx, roomID)
+		if err != nil {
+			return fmt.Errorf("room not found: %w", err)
+		}
+		if !room.Available {
+			return model.NewConflictError("room %s is not open for booking", room.Number)
+		}
+
+		overlap, err := s.reservationRepo.HasOverlap(ctx, roomID, checkIn, checkOut)
+		if err != nil {
+			return fmt.Errorf("failed to check room availability: %w", err)
+		}
+		if overlap {
+			return roomAlreadyBooked(roomID, checkIn, checkOut)
+		}
+
+		// The overlap check above is only a fast path; a concurrent booking can still win the
+		// race, in which case the exclusion constraint rejects the insert
+		if err := s.reservationRepo.Save(ctx, reservation); err != nil {
+			if errors.Is(err, model.ErrReservationOverlap) {
+				return roomAlreadyBooked(roomID, checkIn, checkOut)
+			}
+			return fmt.Errorf("failed to create reservation: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// GetReservation returns a booking to the guest who holds its code or booked it
+// with the given email
+func (s *HotelServiceImpl) GetReservation(ctx context.Context, id int64, access dto.ReservationAccess) (*model.Reservation, error) {
+	reservation, err := s.findGuestReservation(ctx, id, access)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	return reservation, nil
+}
+
+func (s *HotelServiceImpl) ListRoomReservations(ctx context.Context, roomID int64) ([]*model.Reservation, error) {
+	if roomID <= 0 {
+		return nil, model.NewValidationError("invalid room ID")
+	}
+
+	if _, err := s.authorizeRoom(ctx, roomID, model.PermViewReservations); err != nil {
+		return nil, err
+	}
+
+	reservations, err := s.reservationRepo.FindByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// CancelReservation cancels a booking for the guest who holds its code or booked
+// it with the given email
+func (s *HotelServiceImpl) CancelReservation(ctx context.Context, id int64, access dto.ReservationAccess) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		reservation, err := s.findGuestReservation(ctx, id, access)
+		if err != nil {
+			return fmt.Errorf("reservation not found: %w", err)
+		}
+		if reservation.Status == model.ReservationStatusCancelled {
+			return model.NewConflictError("reservation is already cancelled")
+		}
+
+		if err := s.reservationRepo.UpdateStatus(ctx, id, model.ReservationStatusCancelled); err != nil {
+			return fmt.Errorf("failed to cancel reservation: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// findGuestReservation looks a booking up by the code or email the guest
+// presents, never by its sequential ID alone. A wrong code or email is reported
+// exactly like a missing reservation so that IDs cannot be probed.
+func (s *HotelServiceImpl) findGuestReservation(ctx context.Context, id int64, access dto.ReservationAccess) (*model.Reservation, error) {
+	if id <= 0 {
+		return nil, model.NewValidationError("invalid reservation ID")
+	}
+
+	var reservation *model.Reservation
+	var err error
+	switch {
+	case access.Code != "":
+		reservation, err = s.reservationRepo.FindByCode(ctx, access.Code)
+		if err == nil && reservation.ID != id {
+			reservation, err = nil, model.ErrNotFound
+		}
+	case access.GuestEmail != "":
+		reservation, err = s.reservationRepo.FindByID(ctx, id)
+		if err == nil && !strings.EqualFold(reservation.GuestEmail, access.GuestEmail) {
+			reservation, err = nil, model.ErrNotFound
+		}
+	default:
+		return nil, model.NewValidationError("a reservation code or guest email is required")
+	}
+
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, model.NewNotFoundError("reservation with ID %d not found", id)
+	}
+	return reservation, err
+}
+
+// newReservationCode returns 80 random bits as 16 base32 characters, too many
+// to guess
+func newReservationCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", model.NewInternalError(err, "failed to generate reservation code")
+	}
+	return base32.StdEncoding.EncodeToString(b), nil
+}
+
+// Stays are booked per night, so only the calendar date matters
+func truncateToDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"HotelService/application/dto"
+	"HotelService/application/validation"
 	"HotelService/domain/model"
 	"context"
 	"fmt"
@@ -9,23 +10,63 @@ import (
 )
 
 type HotelServiceImpl struct {
-	hotelRepo HotelRepository
-	roomRepo  RoomRepository
+	uow             UnitOfWork
+	hotelRepo       HotelRepository
+	roomRepo        RoomRepository
+	reservationRepo ReservationRepository
+	ratePlanRepo    RatePlanRepository
+	auditRepo       AuditRepository
+	access          accessControl
 }
 
-func NewHotelService(hotelRepo HotelRepository, roomRepo RoomRepository) HotelService {
+// NewHotelService returns the hotel service. Every change a hotelier makes
+// through it is recorded in auditRepo.
+func NewHotelService(uow UnitOfWork, hotelRepo HotelRepository, roomRepo RoomRepository, reservationRepo ReservationRepository, ratePlanRepo RatePlanRepository, staffRepo StaffRepository, auditRepo AuditRepository) HotelService {
 	return &HotelServiceImpl{
-		hotelRepo: hotelRepo,
-		roomRepo:  roomRepo,
+		uow:             uow,
+		hotelRepo:       hotelRepo,
+		roomRepo:        roomRepo,
+		reservationRepo: reservationRepo,
+		ratePlanRepo:    ratePlanRepo,
+		auditRepo:       auditRepo,
+		access:          accessControl{hotelRepo: hotelRepo, staffRepo: staffRepo},
 	}
 }
 
-func (s *HotelServiceImpl) CreateHotel(ctx context.Context, name, address string, rooms []dto.RoomInput) (*model.Hotel, error) {
-	if name == "" {
-		return nil, fmt.Errorf("hotel name is required")
+// CreateHotel creates the hotel with its rooms. An empty currency defaults to
+// model.DefaultCurrency; all room prices must be given in the hotel currency.
+func (s *HotelServiceImpl) CreateHotel(ctx context.Context, name, address, currency string, rooms []dto.RoomInput) (*model.Hotel, error) {
+	if currency == "" {
+		currency = model.DefaultCurrency
 	}
-	if address == "" {
-		return nil, fmt.Errorf("hotel address is required")
+
+	v := validation.New()
+	validateHotel(v, name, address)
+	v.Currency("currency", currency)
+
+	seenNumbers := make(map[string]int, len(rooms))
+	for i, roomInput := range rooms {
+		field := func(name string) string { return validation.Field("rooms", i, name) }
+		validateRoom(v, field, roomInput.Number, roomInput.Type, roomInput.Price, currency, roomInput.Capacity)
+
+		if first, ok := seenNumbers[roomInput.Number]; ok && roomInput.Number != "" {
+			v.Add(field("number"), "duplicates %s", validation.Field("rooms", first, "number"))
+		} else {
+			seenNumbers[roomInput.Number] = i
+		}
+
+		if roomInput.Capacity == 0 {
+			rooms[i].Capacity = model.DefaultRoomCapacity
+		}
+	}
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	ownerID, ok := HotelierFromContext(ctx)
+	if !ok {
+		return nil, model.NewUnauthenticatedError("authentication required")
 	}
 
 	now := time.Now()
@@ -33,42 +74,46 @@ func (s *HotelServiceImpl) CreateHotel(ctx context.Context, name, address string
 	hotel := &model.Hotel{
 		Name:      name,
 		Address:   address,
+		Currency:  currency,
+		OwnerID:   ownerID,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 
-	if err := s.hotelRepo.Save(ctx, hotel); err != nil {
-		return nil, fmt.Errorf("failed to create hotel: %w", err)
-	}
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.hotelRepo.Save(ctx, hotel); err != nil {
+			return fmt.Errorf("failed to create hotel: %w", err)
+		}
+		if err := s.audit(ctx, hotel.ID, model.AuditCreate, model.AuditHotel, hotel.ID, nil, hotel); err != nil {
+			return err
+		}
 
-	if len(rooms) > 0 {
 		for _, roomInput := range rooms {
-			if roomInput.Number == "" {
-				return nil, fmt.Errorf("room number is required")
-			}
-			if roomInput.Type == "" {
-				return nil, fmt.Errorf("room type is required")
-			}
-			if roomInput.Price <= 0 {
-				return nil, fmt.Errorf("room price must be positive")
-			}
-
 			room := &model.Room{
 				HotelID:   hotel.ID,
 				Number:    roomInput.Number,
 				Type:      roomInput.Type,
 				Price:     roomInput.Price,
+				Capacity:  roomInput.Capacity,
 				Available: roomInput.Available,
 				CreatedAt: now,
 				UpdatedAt: now,
 			}
 
 			if err := s.roomRepo.Save(ctx, room); err != nil {
-				return nil, fmt.Errorf("failed to create room %s: %w", roomInput.Number, err)
+				return fmt.Errorf("failed to create room %s: %w", roomInput.Number, err)
+			}
+			if err := s.audit(ctx, hotel.ID, model.AuditCreate, model.AuditRoom, room.ID, nil, room); err != nil {
+				return err
 			}
 
 			hotel.Rooms = append(hotel.Rooms, *room)
 		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return hotel, nil
@@ -76,7 +121,7 @@ func (s *HotelServiceImpl) CreateHotel(ctx context.Context, name, address string
 
 func (s *HotelServiceImpl) GetHotel(ctx context.Context, id int64) (*model.Hotel, error) {
 	if id <= 0 {
-		return nil, fmt.Errorf("invalid hotel ID")
+		return nil, model.NewValidationError("invalid hotel ID")
 	}
 
 	hotel, err := s.hotelRepo.FindByID(ctx, id)
@@ -87,80 +132,255 @@ func (s *HotelServiceImpl) GetHotel(ctx context.Context, id int64) (*model.Hotel
 	return hotel, nil
 }
 
-func (s *HotelServiceImpl) ListHotels(ctx context.Context) ([]*model.Hotel, error) {
-	hotels, err := s.hotelRepo.FindAll(ctx)
+func (s *HotelServiceImpl) ListHotels(ctx context.Context, filter dto.HotelFilter) (*dto.Page[*model.Hotel], error) {
+	v := validation.New()
+	validatePageRequest(v, &filter.PageRequest)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	hotels, nextCursor, err := s.hotelRepo.FindPage(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list hotels: %w", err)
 	}
 
-	return hotels, nil
+	return dto.NewPage(hotels, nextCursor), nil
 }
 
-func (s *HotelServiceImpl) UpdateRoomAvailability(ctx context.Context, roomID int64, available bool) error {
+// UpdateRoomAvailability opens or closes the room and returns it with its new version
+func (s *HotelServiceImpl) UpdateRoomAvailability(ctx context.Context, roomID, version int64, available bool) (*model.Room, error) {
 	if roomID <= 0 {
-		return fmt.Errorf("invalid room ID")
+		return nil, model.NewValidationError("invalid room ID")
 	}
 
-	_, err := s.roomRepo.FindByID(ctx, roomID)
-	if err != nil {
-		return fmt.Errorf("room not found: %w", err)
-	}
+	var room *model.Room
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		room, err = s.authorizeRoom(ctx, roomID, model.PermSetAvailability)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion("room", roomID, room.Version, version); err != nil {
+			return err
+		}
+
+		if err := s.roomRepo.UpdateAvailability(ctx, roomID, available, room.Version); err != nil {
+			return fmt.Errorf("failed to update room availability: %w", err)
+		}
+		if err := s.roomsChanged(ctx, room.HotelID); err != nil {
+			return err
+		}
 
-	if err := s.roomRepo.UpdateAvailability(ctx, roomID, available); err != nil {
-		return fmt.Errorf("failed to update room availability: %w", err)
+		before := *room
+		room.Available = available
+		room.UpdatedAt = time.Now()
+		room.Version++
+		return s.audit(ctx, room.HotelID, model.AuditUpdate, model.AuditRoom, roomID, before, room)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return room, nil
 }
 
-func (s *HotelServiceImpl) FindAvailableRooms(ctx context.Context) ([]*model.Room, error) {
-	rooms, err := s.roomRepo.FindAllAvailable(ctx)
+func (s *HotelServiceImpl) FindAvailableRooms(ctx context.Context, search dto.StaySearch) (*dto.Page[*model.Room], error) {
+	v := validation.New()
+	if search.CheckIn.IsZero() != search.CheckOut.IsZero() {
+		v.Add("check_in", "must be provided together with check_out")
+	}
+	if !search.CheckIn.IsZero() && !search.CheckOut.IsZero() {
+		search.CheckIn = truncateToDate(search.CheckIn)
+		search.CheckOut = truncateToDate(search.CheckOut)
+		v.Check(search.CheckOut.After(search.CheckIn), "check_out", "must be after check_in")
+	}
+	v.Check(search.Guests >= 0, "guests", "must not be negative")
+	v.Check(search.HotelID >= 0, "hotel_id", "must not be negative")
+	if search.MinPrice != nil {
+		v.Check(search.MinPrice.Amount >= 0, "min_price", "must not be negative")
+	}
+	if search.MaxPrice != nil {
+		v.Check(search.MaxPrice.Amount >= 0, "max_price", "must not be negative")
+	}
+	if search.MinPrice != nil && search.MaxPrice != nil {
+		if search.MinPrice.Currency != search.MaxPrice.Currency {
+			v.Add("max_price", "must be in the same currency as min_price")
+		} else {
+			v.Check(search.MinPrice.Amount <= search.MaxPrice.Amount, "min_price", "must not exceed max_price")
+		}
+	}
+	validatePageRequest(v, &search.PageRequest)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	rooms, nextCursor, err := s.roomRepo.FindAvailableForStay(ctx, search)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find available rooms: %w", err)
 	}
 
-	return rooms, nil
+	return dto.NewPage(rooms, nextCursor), nil
 }
 
-func (s *HotelServiceImpl) UpdateHotel(ctx context.Context, id int64, name, address string) (*model.Hotel, error) {
+func (s *HotelServiceImpl) UpdateHotel(ctx context.Context, id, version int64, name, address string) (*model.Hotel, error) {
+	return s.PatchHotel(ctx, id, version, dto.HotelPatch{Name: &name, Address: &address})
+}
+
+// PatchHotel changes only the fields set in patch and validates the result
+func (s *HotelServiceImpl) PatchHotel(ctx context.Context, id, version int64, patch dto.HotelPatch) (*model.Hotel, error) {
 	if id <= 0 {
-		return nil, fmt.Errorf("invalid hotel ID")
+		return nil, model.NewValidationError("invalid hotel ID")
+	}
+
+	var existingHotel *model.Hotel
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		existingHotel, err = s.authorizeHotel(ctx, id, model.PermUpdateHotel)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion("hotel", id, existingHotel.Version, version); err != nil {
+			return err
+		}
+		before := *existingHotel
+
+		if patch.Name != nil {
+			existingHotel.Name = *patch.Name
+		}
+		if patch.Address != nil {
+			existingHotel.Address = *patch.Address
+		}
+
+		v := validation.New()
+		validateHotel(v, existingHotel.Name, existingHotel.Address)
+		if err := v.Err(); err != nil {
+			return err
+		}
+		existingHotel.UpdatedAt = time.Now()
+
+		if err := s.hotelRepo.Update(ctx, existingHotel); err != nil {
+			return fmt.Errorf("failed to update hotel: %w", err)
+		}
+
+		return s.audit(ctx, id, model.AuditUpdate, model.AuditHotel, id, before, existingHotel)
+	})
+	if err != nil {
+		return nil, err
 	}
-	if name == "" {
-		return nil, fmt.Errorf("hotel name is required")
+
+	return existingHotel, nil
+}
+
+// DeleteHotel soft-deletes the hotel and its rooms. They disappear from every
+// listing and can be brought back with RestoreHotel until they are purged. Like
+// DeleteRoom it refuses while a guest has a confirmed stay yet to end.
+func (s *HotelServiceImpl) DeleteHotel(ctx context.Context, id, version int64) error {
+	if id <= 0 {
+		return model.NewValidationError("invalid hotel ID")
 	}
-	if address == "" {
-		return nil, fmt.Errorf("hotel address is required")
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		hotel, err := s.authorizeHotel(ctx, id, model.PermDeleteHotel)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion("hotel", id, hotel.Version, version); err != nil {
+			return err
+		}
+
+		upcoming, err := s.reservationRepo.HasUpcomingStays(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to check upcoming stays: %w", err)
+		}
+		if upcoming {
+			return model.NewConflictError("hotel %d has upcoming reservations", id)
+		}
+
+		if err := s.hotelRepo.SoftDelete(ctx, id, hotel.Version); err != nil {
+			return fmt.Errorf("failed to delete hotel: %w", err)
+		}
+
+		return s.audit(ctx, id, model.AuditDelete, model.AuditHotel, id, hotel, nil)
+	})
+}
+
+// RestoreHotel undoes DeleteHotel; it takes the same permission
+func (s *HotelServiceImpl) RestoreHotel(ctx context.Context, id int64) (*model.Hotel, error) {
+	if id <= 0 {
+		return nil, model.NewValidationError("invalid hotel ID")
 	}
 
-	existingHotel, err := s.hotelRepo.FindByID(ctx, id)
+	var hotel *model.Hotel
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		deleted, err := s.hotelRepo.FindDeletedByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("hotel not found: %w", err)
+		}
+		if err := s.access.authorize(ctx, deleted, model.PermDeleteHotel); err != nil {
+			return err
+		}
+
+		if err := s.hotelRepo.Restore(ctx, id); err != nil {
+			return fmt.Errorf("failed to restore hotel: %w", err)
+		}
+
+		hotel, err = s.hotelRepo.FindByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to load restored hotel: %w", err)
+		}
+
+		return s.audit(ctx, id, model.AuditRestore, model.AuditHotel, id, nil, hotel)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("hotel not found: %w", err)
+		return nil, err
 	}
 
-	existingHotel.Name = name
-	existingHotel.Address = address
-	existingHotel.UpdatedAt = time.Now()
+	return hotel, nil
+}
+
+// DefaultDeletedHotelRetention is how long a deleted hotel can be restored
+// before the purge job removes it for good
+const DefaultDeletedHotelRetention = 30 * 24 * time.Hour
+
+// PurgeDeletedHotels removes hotels soft-deleted more than retention ago for
+// good, with their rooms, reservations, rate plans and staff, and records the
+// purge in their audit log, which is kept. A hotel whose guests still have a
+// confirmed stay ahead is left for a later run. It runs as a maintenance job,
+// not on behalf of a hotelier.
+func (s *HotelServiceImpl) PurgeDeletedHotels(ctx context.Context, retention time.Duration) (int64, error) {
+	if retention < 0 {
+		return 0, model.NewValidationError("retention cannot be negative")
+	}
+
+	var purged int64
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		hotels, err := s.hotelRepo.PurgeDeleted(ctx, time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+
+		for _, hotel := range hotels {
+			if err := saveAudit(ctx, s.auditRepo, 0, hotel.ID, model.AuditPurge, model.AuditHotel, hotel.ID, hotel, nil); err != nil {
+				return err
+			}
+		}
 
-	if err := s.hotelRepo.Update(ctx, existingHotel); err != nil {
-		return nil, fmt.Errorf("failed to update hotel: %w", err)
+		purged = int64(len(hotels))
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted hotels: %w", err)
 	}
 
-	return existingHotel, nil
+	return purged, nil
 }
 
-func (s *HotelServiceImpl) AddRoomToHotel(ctx context.Context, hotelID int64, number, roomType string, price float64, available bool) (*model.Room, error) {
+func (s *HotelServiceImpl) AddRoomToHotel(ctx context.Context, hotelID int64, number, roomType string, price model.Money, capacity int, available bool) (*model.Room, error) {
 	if hotelID <= 0 {
-		return nil, fmt.Errorf("invalid hotel ID")
-	}
-	if number == "" {
-		return nil, fmt.Errorf("room number is required")
-	}
-	if roomType == "" {
-		return nil, fmt.Errorf("room type is required")
+		return nil, model.NewValidationError("invalid hotel ID")
 	}
-	if price <= 0 {
-		return nil, fmt.Errorf("room price must be positive")
+	if capacity == 0 {
+		capacity = model.DefaultRoomCapacity
 	}
 
 	now := time.Now()
@@ -169,63 +389,195 @@ func (s *HotelServiceImpl) AddRoomToHotel(ctx context.Context, hotelID int64, nu
 		Number:    number,
 		Type:      roomType,
 		Price:     price,
+		Capacity:  capacity,
 		Available: available,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 
-	if err := s.roomRepo.Save(ctx, room); err != nil {
-		return nil, fmt.Errorf("failed to add room: %w", err)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		hotel, err := s.authorizeHotel(ctx, hotelID, model.PermManageRooms)
+		if err != nil {
+			return err
+		}
+
+		v := validation.New()
+		validateRoom(v, fieldName, number, roomType, price, hotel.Currency, capacity)
+		if err := v.Err(); err != nil {
+			return err
+		}
+
+		if err := s.roomRepo.Save(ctx, room); err != nil {
+			return fmt.Errorf("failed to add room: %w", err)
+		}
+		if err := s.roomsChanged(ctx, hotelID); err != nil {
+			return err
+		}
+
+		return s.audit(ctx, hotelID, model.AuditCreate, model.AuditRoom, room.ID, nil, room)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return room, nil
 }
 
-func (s *HotelServiceImpl) UpdateRoom(ctx context.Context, id int64, number, roomType string, price float64, available bool) (*model.Room, error) {
+func (s *HotelServiceImpl) GetRoom(ctx context.Context, id int64) (*model.Room, error) {
 	if id <= 0 {
-		return nil, fmt.Errorf("invalid room ID")
-	}
-	if number == "" {
-		return nil, fmt.Errorf("room number is required")
+		return nil, model.NewValidationError("invalid room ID")
 	}
-	if roomType == "" {
-		return nil, fmt.Errorf("room type is required")
+
+	room, err := s.roomRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room: %w", err)
 	}
-	if price <= 0 {
-		return nil, fmt.Errorf("room price must be positive")
+
+	return room, nil
+}
+
+// UpdateRoom replaces every field of the room except a capacity of 0, which
+// keeps the current one
+func (s *HotelServiceImpl) UpdateRoom(ctx context.Context, id, version int64, number, roomType string, price model.Money, capacity int, available bool) (*model.Room, error) {
+	patch := dto.RoomPatch{Number: &number, Type: &roomType, Price: &price, Available: &available}
+	if capacity != 0 {
+		patch.Capacity = &capacity
 	}
+	return s.PatchRoom(ctx, id, version, patch)
+}
 
-	existingRoom, err := s.roomRepo.FindByID(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("room not found: %w", err)
+// PatchRoom changes only the fields set in patch and validates the result
+func (s *HotelServiceImpl) PatchRoom(ctx context.Context, id, version int64, patch dto.RoomPatch) (*model.Room, error) {
+	if id <= 0 {
+		return nil, model.NewValidationError("invalid room ID")
 	}
 
-	existingRoom.Number = number
-	existingRoom.Type = roomType
-	existingRoom.Price = price
-	existingRoom.Available = available
-	existingRoom.UpdatedAt = time.Now()
+	var existingRoom *model.Room
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		existingRoom, err = s.authorizeRoom(ctx, id, model.PermUpdateRooms)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion("room", id, existingRoom.Version, version); err != nil {
+			return err
+		}
+		before := *existingRoom
 
-	if err := s.roomRepo.Update(ctx, existingRoom); err != nil {
-		return nil, fmt.Errorf("failed to update room: %w", err)
+		if patch.Number != nil {
+			existingRoom.Number = *patch.Number
+		}
+		if patch.Type != nil {
+			existingRoom.Type = *patch.Type
+		}
+		if patch.Price != nil {
+			existingRoom.Price = *patch.Price
+		}
+		if patch.Capacity != nil {
+			existingRoom.Capacity = *patch.Capacity
+		}
+		if patch.Available != nil {
+			existingRoom.Available = *patch.Available
+		}
+
+		// Stored prices are always in the hotel currency
+		v := validation.New()
+		validateRoom(v, fieldName, existingRoom.Number, existingRoom.Type, existingRoom.Price, before.Price.Currency, existingRoom.Capacity)
+		v.Check(existingRoom.Capacity != 0, "capacity", "must be positive")
+		if err := v.Err(); err != nil {
+			return err
+		}
+		existingRoom.UpdatedAt = time.Now()
+
+		if err := s.roomRepo.Update(ctx, existingRoom); err != nil {
+			return fmt.Errorf("failed to update room: %w", err)
+		}
+		if err := s.roomsChanged(ctx, existingRoom.HotelID); err != nil {
+			return err
+		}
+
+		return s.audit(ctx, existingRoom.HotelID, model.AuditUpdate, model.AuditRoom, id, before, existingRoom)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return existingRoom, nil
 }
 
-func (s *HotelServiceImpl) DeleteRoom(ctx context.Context, id int64) error {
+func (s *HotelServiceImpl) DeleteRoom(ctx context.Context, id, version int64) error {
 	if id <= 0 {
-		return fmt.Errorf("invalid room ID")
+		return model.NewValidationError("invalid room ID")
 	}
 
-	_, err := s.roomRepo.FindByID(ctx, id)
-	if err != nil {
-		return fmt.Errorf("room not found: %w", err)
-	}
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		room, err := s.authorizeRoom(ctx, id, model.PermManageRooms)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion("room", id, room.Version, version); err != nil {
+			return err
+		}
 
-	if err := s.roomRepo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete room: %w", err)
+		if err := s.roomRepo.Delete(ctx, id, room.Version); err != nil {
+			return fmt.Errorf("failed to delete room: %w", err)
+		}
+		if err := s.roomsChanged(ctx, room.HotelID); err != nil {
+			return err
+		}
+
+		return s.audit(ctx, room.HotelID, model.AuditDelete, model.AuditRoom, id, room, nil)
+	})
+}
+
+// roomsChanged bumps the version of the hotel after one of its rooms changed,
+// so that the hotel ETag covers its rooms
+func (s *HotelServiceImpl) roomsChanged(ctx context.Context, hotelID int64) error {
+	if err := s.hotelRepo.BumpVersion(ctx, hotelID); err != nil {
+		return fmt.Errorf("failed to update hotel version: %w", err)
 	}
+	return nil
+}
 
+// AnyVersion skips the version check of an update or delete, like If-Match: *
+const AnyVersion int64 = 0
+
+// checkVersion fails with ErrPreconditionFailed unless version is AnyVersion or
+// the current version of the entity
+func checkVersion(entity string, id, current, version int64) error {
+	if version != AnyVersion && version != current {
+		return model.NewPreconditionFailedError("%s %d has changed since version %d", entity, id, version)
+	}
 	return nil
 }
+
+func validateHotel(v *validation.Validator, name, address string) {
+	if v.Required("name", name) {
+		v.MaxLength("name", name, model.MaxHotelNameLength)
+	}
+	v.Required("address", address)
+}
+
+// validateRoom checks room fields; field maps a field name to its path in the request
+func validateRoom(v *validation.Validator, field func(string) string, number, roomType string, price model.Money, currency string, capacity int) {
+	if v.Required(field("number"), number) {
+		v.MaxLength(field("number"), number, model.MaxRoomNumberLength)
+	}
+	if v.Required(field("type"), roomType) {
+		v.MaxLength(field("type"), roomType, model.MaxRoomTypeLength)
+	}
+	v.Price(field("price"), price, currency)
+	v.Check(capacity >= 0, field("capacity"), "must not be negative")
+}
+
+// validatePageRequest checks the limit and applies the default page size
+func validatePageRequest(v *validation.Validator, page *dto.PageRequest) {
+	v.Check(page.Limit >= 0 && page.Limit <= dto.MaxPageLimit, "limit", fmt.Sprintf("must be between 1 and %d", dto.MaxPageLimit))
+	if page.Limit == 0 {
+		page.Limit = dto.DefaultPageLimit
+	}
+}
+
+func fieldName(name string) string {
+	return name
+}
@@ -0,0 +1,105 @@
+package validation
+
+import (
+	"HotelService/domain/model"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validator collects field errors so a request can be rejected with all of its problems at once
+type Validator struct {
+	fields []model.FieldError
+}
+
+func New() *Validator {
+	return &Validator{}
+}
+
+// Add records an error for field
+func (v *Validator) Add(field, format string, args ...interface{}) {
+	v.fields = append(v.fields, model.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Check records message for field when ok is false
+func (v *Validator) Check(ok bool, field, message string) {
+	if !ok {
+		v.Add(field, "%s", message)
+	}
+}
+
+func (v *Validator) Required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, "is required")
+		return false
+	}
+	return true
+}
+
+// MaxLength counts characters rather than bytes, like Postgres VARCHAR(n)
+func (v *Validator) MaxLength(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.Add(field, "must be at most %d characters", max)
+	}
+}
+
+func (v *Validator) Positive(field string, value float64) {
+	if value <= 0 {
+		v.Add(field, "must be positive")
+	}
+}
+
+// Price requires a positive amount in the expected currency that fits the
+// price columns
+func (v *Validator) Price(field string, price model.Money, currency string) {
+	if !price.IsPositive() {
+		v.Add(field, "must be positive")
+	}
+	if max := model.MaxPrice(price.Currency); price.Amount > max.Amount {
+		v.Add(field, "must be at most %s", max.Decimal())
+	}
+	if price.Currency != currency {
+		v.Add(field, "must be in %s", currency)
+	}
+}
+
+// Money parses a decimal amount in currency, recording an error for field when
+// the currency is not supported or the amount is malformed or too precise for it
+func (v *Validator) Money(field, amount, currency string) model.Money {
+	currency = strings.ToUpper(currency)
+	if !model.IsSupportedCurrency(currency) {
+		v.Add(field, "has an unsupported currency %q", currency)
+		return model.Money{}
+	}
+
+	price, err := model.ParseMoney(amount, currency)
+	if err != nil {
+		v.Add(field, "%s", err.Error())
+		return model.Money{}
+	}
+
+	return price
+}
+
+func (v *Validator) Currency(field, code string) {
+	if !model.IsSupportedCurrency(code) {
+		v.Add(field, "must be a supported ISO 4217 currency code")
+	}
+}
+
+func (v *Validator) Valid() bool {
+	return len(v.fields) == 0
+}
+
+// Err returns a *model.ValidationError with every recorded field error, or nil
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	return &model.ValidationError{Fields: v.fields}
+}
+
+// Field builds a nested field path such as rooms[2].price
+func Field(prefix string, index int, name string) string {
+	return fmt.Sprintf("%s[%d].%s", prefix, index, name)
+}
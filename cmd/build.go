@@ -0,0 +1,46 @@
+package main
+
+import (
+	"HotelService/api/rest/controller"
+	"runtime"
+	"runtime/debug"
+)
+
+// Set at link time, e.g.
+//
+//	go build -ldflags "-X main.commit=$(git rev-parse HEAD) -X main.buildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd
+var (
+	commit    string
+	buildTime string
+)
+
+// buildInfo describes this binary for /version. Without a link-time commit it
+// uses the revision the go command embeds when building from a checkout.
+func buildInfo() controller.BuildInfo {
+	info := controller.BuildInfo{Commit: commit, BuildTime: buildTime, GoVersion: runtime.Version()}
+
+	if info.Commit == "" {
+		if build, ok := debug.ReadBuildInfo(); ok {
+			modified := false
+			for _, setting := range build.Settings {
+				switch setting.Key {
+				case "vcs.revision":
+					info.Commit = setting.Value
+				case "vcs.modified":
+					modified = setting.Value == "true"
+				}
+			}
+			if info.Commit != "" && modified {
+				info.Commit += "-dirty"
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
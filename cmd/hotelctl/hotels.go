@@ -0,0 +1,298 @@
+package main
+
+import (
+	"HotelService/application/dto"
+	"HotelService/application/service"
+	"HotelService/domain/model"
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+)
+
+const (
+	hotelChoices = "create|list|show|update|delete|restore"
+	roomChoices  = "add|list|update|availability"
+)
+
+func (a *app) hotel(ctx context.Context, args []string) error {
+	name, args, err := subcommand("hotel", args, hotelChoices)
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "create":
+		fs := newFlagSet("hotel create")
+		hotelName := fs.String("name", "", "hotel name (required)")
+		address := fs.String("address", "", "street address (required)")
+		currency := fs.String("currency", model.DefaultCurrency, "ISO 4217 currency of all room prices")
+		fs.Parse(args)
+
+		ctx, err := a.actor(ctx)
+		if err != nil {
+			return err
+		}
+
+		hotel, err := a.hotels.CreateHotel(ctx, *hotelName, *address, *currency, nil)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.out, "created hotel %d\n", hotel.ID)
+		return nil
+
+	case "list":
+		fs := newFlagSet("hotel list")
+		filter := dto.HotelFilter{}
+		fs.StringVar(&filter.Name, "name", "", "only hotels whose name contains this text")
+		fs.StringVar(&filter.Address, "address", "", "only hotels whose address contains this text")
+		fs.StringVar(&filter.Sort, "sort", "", "created_at, -created_at, name or -name")
+		fs.IntVar(&filter.Limit, "limit", dto.MaxPageLimit, "hotels fetched per page")
+		fs.Parse(args)
+
+		tw := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tADDRESS\tCURRENCY\tOWNER")
+		err := a.eachHotel(ctx, filter, func(hotel *model.Hotel) error {
+			owner := "-"
+			if hotel.OwnerID != 0 {
+				owner = fmt.Sprint(hotel.OwnerID)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", hotel.ID, hotel.Name, hotel.Address, hotel.Currency, owner)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return tw.Flush()
+
+	case "show":
+		fs := newFlagSet("hotel show")
+		id := fs.Int64("id", 0, "hotel ID (required)")
+		fs.Parse(args)
+
+		hotel, err := a.hotels.GetHotel(ctx, *id)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.out, "%d\t%s\n%s\nprices in %s\n\n", hotel.ID, hotel.Name, hotel.Address, hotel.Currency)
+		return a.printRooms(hotel.Rooms)
+
+	case "update":
+		fs := newFlagSet("hotel update")
+		id := fs.Int64("id", 0, "hotel ID (required)")
+		hotelName := fs.String("name", "", "new name; unchanged when omitted")
+		address := fs.String("address", "", "new address; unchanged when omitted")
+		fs.Parse(args)
+
+		ctx, err := a.actor(ctx)
+		if err != nil {
+			return err
+		}
+
+		hotel, err := a.hotels.GetHotel(ctx, *id)
+		if err != nil {
+			return err
+		}
+		if isSet(fs, "name") {
+			hotel.Name = *hotelName
+		}
+		if isSet(fs, "address") {
+			hotel.Address = *address
+		}
+
+		if _, err := a.hotels.UpdateHotel(ctx, hotel.ID, hotel.Version, hotel.Name, hotel.Address); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.out, "updated hotel %d\n", hotel.ID)
+		return nil
+
+	case "delete", "restore":
+		fs := newFlagSet("hotel " + name)
+		id := fs.Int64("id", 0, "hotel ID (required)")
+		fs.Parse(args)
+
+		ctx, err := a.actor(ctx)
+		if err != nil {
+			return err
+		}
+
+		if name == "delete" {
+			err = a.hotels.DeleteHotel(ctx, *id, service.AnyVersion)
+		} else {
+			_, err = a.hotels.RestoreHotel(ctx, *id)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.out, "%sd hotel %d\n", name, *id)
+		return nil
+
+	default:
+		return unknownSubcommand("hotel", name, hotelChoices)
+	}
+}
+
+func (a *app) room(ctx context.Context, args []string) error {
+	name, args, err := subcommand("room", args, roomChoices)
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "add":
+		fs := newFlagSet("room add")
+		hotelID := fs.Int64("hotel", 0, "hotel ID (required)")
+		number := fs.String("number", "", "room number (required)")
+		roomType := fs.String("type", "", "room type (required)")
+		price := fs.String("price", "", "nightly price in the hotel currency, e.g. 120.00 (required)")
+		capacity := fs.Int("capacity", model.DefaultRoomCapacity, "maximum number of guests")
+		available := fs.Bool("available", true, "whether the room can be booked")
+		fs.Parse(args)
+
+		ctx, err := a.actor(ctx)
+		if err != nil {
+			return err
+		}
+
+		hotel, err := a.hotels.GetHotel(ctx, *hotelID)
+		if err != nil {
+			return err
+		}
+
+		amount, err := parsePriceFlag(*price, hotel.Currency)
+		if err != nil {
+			return err
+		}
+
+		room, err := a.hotels.AddRoomToHotel(ctx, hotel.ID, *number, *roomType, amount, *capacity, *available)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.out, "added room %d\n", room.ID)
+		return nil
+
+	case "list":
+		fs := newFlagSet("room list")
+		hotelID := fs.Int64("hotel", 0, "hotel ID (required)")
+		fs.Parse(args)
+
+		hotel, err := a.hotels.GetHotel(ctx, *hotelID)
+		if err != nil {
+			return err
+		}
+		return a.printRooms(hotel.Rooms)
+
+	case "update":
+		fs := newFlagSet("room update")
+		id := fs.Int64("id", 0, "room ID (required)")
+		number := fs.String("number", "", "new room number")
+		roomType := fs.String("type", "", "new room type")
+		price := fs.String("price", "", "new nightly price in the hotel currency")
+		capacity := fs.Int("capacity", 0, "new maximum number of guests")
+		available := fs.Bool("available", false, "whether the room can be booked")
+		fs.Parse(args)
+
+		ctx, err := a.actor(ctx)
+		if err != nil {
+			return err
+		}
+
+		room, err := a.hotels.GetRoom(ctx, *id)
+		if err != nil {
+			return err
+		}
+		if isSet(fs, "number") {
+			room.Number = *number
+		}
+		if isSet(fs, "type") {
+			room.Type = *roomType
+		}
+		if isSet(fs, "price") {
+			if room.Price, err = parsePriceFlag(*price, room.Price.Currency); err != nil {
+				return err
+			}
+		}
+		if isSet(fs, "capacity") {
+			room.Capacity = *capacity
+		}
+		if isSet(fs, "available") {
+			room.Available = *available
+		}
+
+		if _, err := a.hotels.UpdateRoom(ctx, room.ID, room.Version, room.Number, room.Type, room.Price, room.Capacity, room.Available); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.out, "updated room %d\n", room.ID)
+		return nil
+
+	case "availability":
+		fs := newFlagSet("room availability")
+		id := fs.Int64("id", 0, "room ID (required)")
+		available := fs.Bool("available", true, "whether the room can be booked")
+		fs.Parse(args)
+
+		ctx, err := a.actor(ctx)
+		if err != nil {
+			return err
+		}
+
+		if _, err := a.hotels.UpdateRoomAvailability(ctx, *id, service.AnyVersion, *available); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.out, "room %d available: %v\n", *id, *available)
+		return nil
+
+	default:
+		return unknownSubcommand("room", name, roomChoices)
+	}
+}
+
+func (a *app) printRooms(rooms []model.Room) error {
+	tw := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNUMBER\tTYPE\tPRICE\tCAPACITY\tAVAILABLE")
+	for _, room := range rooms {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%v\n", room.ID, room.Number, room.Type, room.Price, room.Capacity, room.Available)
+	}
+	return tw.Flush()
+}
+
+// eachHotel walks every page of hotels matching filter
+func (a *app) eachHotel(ctx context.Context, filter dto.HotelFilter, fn func(hotel *model.Hotel) error) error {
+	for {
+		page, err := a.hotels.ListHotels(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		for _, hotel := range page.Items {
+			if err := fn(hotel); err != nil {
+				return err
+			}
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+func parsePriceFlag(price, currency string) (model.Money, error) {
+	if price == "" {
+		return model.Money{}, errors.New("-price is required")
+	}
+
+	amount, err := model.ParseMoney(price, currency)
+	if err != nil {
+		return model.Money{}, fmt.Errorf("invalid -price: %w", err)
+	}
+
+	return amount, nil
+}
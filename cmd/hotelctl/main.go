@@ -0,0 +1,197 @@
+// Command hotelctl operates the hotel service from the command line. It works
+// directly against the database configured through the same CONFIG_FILE and
+// DB_* (or, with -storage sqlite, SQLITE_*) environment variables as the server.
+package main
+
+import (
+	"HotelService/application/service"
+	"HotelService/infrastructure/config"
+	"HotelService/infrastructure/db"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const usage = `Usage: hotelctl [-as email] [-storage postgres|sqlite] <command> [arguments]
+
+Commands:
+  migrate up|down|status               Manage the database schema
+  seed                                 Load the sample hotels
+  hotel create|list|show|update|delete|restore
+                                       Manage hotels
+  room add|list|update|availability    Manage rooms
+  import -file FILE                    Create hotels and rooms from a JSON file
+  export [-file FILE]                  Write every hotel with its rooms as JSON
+  purge [-older-than DURATION]         Remove deleted hotels past the retention period
+
+Commands that change hotels act on behalf of the hotelier given with -as
+(or HOTELCTL_AS) and are subject to the same ownership and role checks as the API.
+Run "hotelctl <command> -h" for the flags of a command.
+`
+
+// errUsage reports that the command line was malformed; usage has already been printed
+var errUsage = errors.New("invalid usage")
+
+type app struct {
+	db           *sql.DB
+	newMigrator  func(*sql.DB) (*db.Migrator, error)
+	hotels       service.HotelService
+	hotelierRepo service.HotelierRepository
+	as           string
+	out          io.Writer
+}
+
+func main() {
+	global := flag.NewFlagSet("hotelctl", flag.ExitOnError)
+	as := global.String("as", os.Getenv("HOTELCTL_AS"), "email of the hotelier to act as")
+	storage := global.String("storage", "postgres", `database to operate on: "postgres" or "sqlite"`)
+	global.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	global.Parse(os.Args[1:])
+
+	if global.NArg() == 0 {
+		global.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadStorage(*storage, os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "hotelctl: invalid configuration:", err)
+		os.Exit(2)
+	}
+
+	var a *app
+	switch cfg.Storage {
+	case config.StoragePostgres:
+		database, err := db.NewPostgresDB(cfg.PostgresConfig())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hotelctl:", err)
+			os.Exit(1)
+		}
+		defer database.Close()
+
+		a = newApp(database, db.NewRepositories(database), db.NewMigrator, *as, os.Stdout)
+	case config.StorageSQLite:
+		database, err := db.NewSQLiteDB(cfg.SQLiteConfig())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hotelctl:", err)
+			os.Exit(1)
+		}
+		defer database.Close()
+
+		a = newApp(database, db.NewSQLiteRepositories(database), db.NewSQLiteMigrator, *as, os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "hotelctl: unknown storage %q\n", *storage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = a.run(ctx, global.Args())
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "hotelctl:", err)
+		os.Exit(1)
+	}
+}
+
+func newApp(database *sql.DB, repos service.Repositories, newMigrator func(*sql.DB) (*db.Migrator, error), as string, out io.Writer) *app {
+	return &app{
+		db:           database,
+		newMigrator:  newMigrator,
+		hotels:       service.NewHotelService(repos.UnitOfWork, repos.Hotels, repos.Rooms, repos.Reservations, repos.RatePlans, repos.Staff, repos.Audit),
+		hotelierRepo: repos.Hoteliers,
+		as:           as,
+		out:          out,
+	}
+}
+
+func (a *app) run(ctx context.Context, args []string) error {
+	switch args[0] {
+	case "migrate":
+		return a.migrate(ctx, args[1:])
+	case "seed":
+		return a.seed(ctx, args[1:])
+	case "hotel":
+		return a.hotel(ctx, args[1:])
+	case "room":
+		return a.room(ctx, args[1:])
+	case "import":
+		return a.importHotels(ctx, args[1:])
+	case "export":
+		return a.exportHotels(ctx, args[1:])
+	case "purge":
+		return a.purge(ctx, args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(a.out, usage)
+		return nil
+	default:
+		fmt.Fprintf(os.Stderr, "hotelctl: unknown command %q\n\n%s", args[0], usage)
+		return errUsage
+	}
+}
+
+// purge removes deleted hotels for good, like the server does periodically
+func (a *app) purge(ctx context.Context, args []string) error {
+	fs := newFlagSet("purge")
+	olderThan := fs.Duration("older-than", service.DefaultDeletedHotelRetention, "only hotels deleted longer ago than this")
+	fs.Parse(args)
+
+	purged, err := a.hotels.PurgeDeletedHotels(ctx, *olderThan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "purged %d deleted hotels\n", purged)
+	return nil
+}
+
+// actor returns ctx acting on behalf of the hotelier named by -as
+func (a *app) actor(ctx context.Context) (context.Context, error) {
+	if a.as == "" {
+		return nil, errors.New("this command changes hotels; pass -as <hotelier email>")
+	}
+
+	hotelier, err := a.hotelierRepo.FindByEmail(ctx, a.as)
+	if err != nil {
+		return nil, fmt.Errorf("cannot act as %s: %w", a.as, err)
+	}
+
+	return service.WithHotelier(ctx, hotelier.ID), nil
+}
+
+// subcommand splits "<name> [flags]" off args, printing the choices when it is missing
+func subcommand(command string, args []string, choices string) (string, []string, error) {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: hotelctl %s %s\n", command, choices)
+		return "", nil, errUsage
+	}
+	return args[0], args[1:], nil
+}
+
+func unknownSubcommand(command, name, choices string) error {
+	fmt.Fprintf(os.Stderr, "hotelctl: unknown %s command %q\nUsage: hotelctl %s %s\n", command, name, command, choices)
+	return errUsage
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("hotelctl "+name, flag.ExitOnError)
+}
+
+// isSet reports whether the flag was given on the command line
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
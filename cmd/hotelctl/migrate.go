@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateChoices = "up|down|status"
+
+func (a *app) migrate(ctx context.Context, args []string) error {
+	name, args, err := subcommand("migrate", args, migrateChoices)
+	if err != nil {
+		return err
+	}
+
+	migrator, err := a.newMigrator(a.db)
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "up":
+		newFlagSet("migrate up").Parse(args)
+
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Fprintf(a.out, "applied %03d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(a.out, "schema is up to date")
+		}
+		return nil
+
+	case "down":
+		fs := newFlagSet("migrate down")
+		steps := fs.Int("steps", 1, "number of migrations to revert")
+		fs.Parse(args)
+
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, migration := range reverted {
+			fmt.Fprintf(a.out, "reverted %03d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+
+	case "status":
+		newFlagSet("migrate status").Parse(args)
+
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%03d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return tw.Flush()
+
+	default:
+		return unknownSubcommand("migrate", name, migrateChoices)
+	}
+}
+
+func (a *app) seed(ctx context.Context, args []string) error {
+	newFlagSet("seed").Parse(args)
+
+	migrator, err := a.newMigrator(a.db)
+	if err != nil {
+		return err
+	}
+
+	if err := migrator.Seed(ctx); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.out, "sample data loaded")
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"HotelService/application/dto"
+	"HotelService/domain/model"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// hotelRecord is one hotel in the import/export file. Export writes exactly
+// what import reads, so a dump can be loaded into another database.
+type hotelRecord struct {
+	Name     string       `json:"name"`
+	Address  string       `json:"address"`
+	Currency string       `json:"currency"`
+	Rooms    []roomRecord `json:"rooms"`
+}
+
+type roomRecord struct {
+	Number    string      `json:"number"`
+	Type      string      `json:"type"`
+	Price     model.Money `json:"price"`
+	Capacity  int         `json:"capacity"`
+	Available bool        `json:"available"`
+}
+
+// importHotels creates every hotel in the file with its rooms. Each hotel is
+// created atomically; a failing hotel is reported and the rest are still imported.
+func (a *app) importHotels(ctx context.Context, args []string) error {
+	fs := newFlagSet("import")
+	file := fs.String("file", "", `JSON file holding an array of hotels; "-" reads stdin (required)`)
+	fs.Parse(args)
+
+	if *file == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	ctx, err := a.actor(ctx)
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var records []hotelRecord
+	if err := json.NewDecoder(in).Decode(&records); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", *file, err)
+	}
+
+	failed := 0
+	for i, record := range records {
+		rooms := make([]dto.RoomInput, len(record.Rooms))
+		for j, room := range record.Rooms {
+			rooms[j] = dto.RoomInput{
+				Number:    room.Number,
+				Type:      room.Type,
+				Price:     room.Price,
+				Capacity:  room.Capacity,
+				Available: room.Available,
+			}
+		}
+
+		hotel, err := a.hotels.CreateHotel(ctx, record.Name, record.Address, record.Currency, rooms)
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "hotel #%d (%s): %v\n", i+1, record.Name, err)
+			continue
+		}
+
+		fmt.Fprintf(a.out, "imported hotel %d %s with %d rooms\n", hotel.ID, hotel.Name, len(hotel.Rooms))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d hotels failed to import", failed, len(records))
+	}
+	return nil
+}
+
+// exportHotels writes every hotel with its rooms in the import format
+func (a *app) exportHotels(ctx context.Context, args []string) error {
+	fs := newFlagSet("export")
+	file := fs.String("file", "-", `output file; "-" writes stdout`)
+	fs.Parse(args)
+
+	records := []hotelRecord{}
+	err := a.eachHotel(ctx, dto.HotelFilter{PageRequest: dto.PageRequest{Sort: "created_at", Limit: dto.MaxPageLimit}}, func(summary *model.Hotel) error {
+		hotel, err := a.hotels.GetHotel(ctx, summary.ID)
+		if err != nil {
+			return err
+		}
+
+		record := hotelRecord{
+			Name:     hotel.Name,
+			Address:  hotel.Address,
+			Currency: hotel.Currency,
+			Rooms:    make([]roomRecord, len(hotel.Rooms)),
+		}
+		for i, room := range hotel.Rooms {
+			record.Rooms[i] = roomRecord{
+				Number:    room.Number,
+				Type:      room.Type,
+				Price:     room.Price,
+				Capacity:  room.Capacity,
+				Available: room.Available,
+			}
+		}
+
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	out := a.out
+	var f *os.File
+	if *file != "-" {
+		if f, err = os.Create(*file); err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(records); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	if f != nil {
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+		fmt.Fprintf(a.out, "exported %d hotels to %s\n", len(records), *file)
+	}
+	return nil
+}
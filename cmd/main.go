@@ -2,46 +2,185 @@ package main
 
 import (
 	"HotelService/api/rest/controller"
+	"HotelService/application/service"
+	"HotelService/infrastructure/config"
 	"HotelService/infrastructure/db"
+	"HotelService/infrastructure/memory"
+	"context"
 	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
-	"net/http"
+	"log/slog"
 	"os"
+	"sync"
+	"time"
 )
 
+// purgeInterval is how often the server looks for deleted hotels past their
+// retention and for expired idempotency keys
+const purgeInterval = time.Hour
+
 func main() {
-	dbConfig := db.DefaultConfig()
+	if err := run(); err != nil {
+		slog.Error("server stopped with error", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
 
-	database, err := db.NewPostgresDB(dbConfig)
+// run serves the API until shutdown. It returns only after in-flight requests
+// and background jobs have finished, so that deferred database closes are clean.
+// Errors before the server starts listening say that it failed to start.
+func run() error {
+	cfg, err := config.Load(os.Args[0], os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return fmt.Errorf("failed to start: invalid configuration: %w", err)
 	}
-	defer database.Close()
 
-	if err := runMigrations(database); err != nil {
-		log.Fatal("Failed to run migrations:", err)
+	// Draining is closed when shutdown begins, so that /readyz fails first
+	draining := make(chan struct{})
+	opts := controller.Options{
+		AuthSecret:         []byte(cfg.Auth.TokenSecret),
+		TokenTTL:           cfg.Auth.TokenTTL,
+		RequestTimeout:     cfg.Server.RequestTimeout,
+		MaxRequestBodySize: cfg.Server.MaxRequestBodySize,
+		Build:              buildInfo(),
+		Draining:           draining,
+	}
+
+	var repos service.Repositories
+	switch cfg.Storage {
+	case config.StoragePostgres:
+		database, err := db.NewPostgresDB(cfg.PostgresConfig())
+		if err != nil {
+			return fmt.Errorf("failed to start: failed to connect to database: %w", err)
+		}
+		defer database.Close()
+
+		migrator, err := runMigrations(database, db.NewMigrator, cfg.SeedSampleData)
+		if err != nil {
+			return fmt.Errorf("failed to start: failed to run migrations: %w", err)
+		}
+
+		repos = db.NewRepositories(database)
+		opts.Database, opts.Schema = database, migrator
+	case config.StorageSQLite:
+		database, err := db.NewSQLiteDB(cfg.SQLiteConfig())
+		if err != nil {
+			return fmt.Errorf("failed to start: failed to open database: %w", err)
+		}
+		defer database.Close()
+
+		migrator, err := runMigrations(database, db.NewSQLiteMigrator, cfg.SeedSampleData)
+		if err != nil {
+			return fmt.Errorf("failed to start: failed to run migrations: %w", err)
+		}
+
+		repos = db.NewSQLiteRepositories(database)
+		opts.Database, opts.Schema = database, migrator
+	case config.StorageMemory:
+		slog.Warn("using in-memory storage; all data is lost on exit")
+		repos = memory.NewRepositories()
 	}
 
-	mux := controller.SetupRoutes(database)
+	// Background jobs stop with the server, before the database is closed
+	jobs, stopJobs := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer stopJobs()
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		purgeDeletedHotels(jobs, service.NewHotelService(repos.UnitOfWork, repos.Hotels, repos.Rooms, repos.Reservations, repos.RatePlans, repos.Staff, repos.Audit), cfg.DeletedHotelRetention)
+	}()
+	go func() {
+		defer wg.Done()
+		purgeIdempotencyKeys(jobs, service.NewIdempotencyService(repos.Idempotency))
+	}()
 
-	port := os.Getenv("PORT")
+	handler := controller.SetupRoutes(repos, opts)
 
-	if err := http.ListenAndServe(":"+port, mux); err != nil {
-		log.Fatal("Server failed to start:", err)
+	if err := serve(cfg.Server, handler, draining); err != nil {
+		return fmt.Errorf("server failed: %w", err)
 	}
+
+	slog.Info("server stopped")
+	return nil
 }
 
-func runMigrations(db *sql.DB) error {
-	migrationSQL, err := os.ReadFile("infrastructure/db/migrations/001_create_tables.sql")
+// runMigrations brings the schema up to date and loads the sample data when seed
+// is true. The migrator is returned for the readiness checks.
+func runMigrations(database *sql.DB, newMigrator func(*sql.DB) (*db.Migrator, error), seed bool) (*db.Migrator, error) {
+	ctx := context.Background()
+
+	migrator, err := newMigrator(database)
 	if err != nil {
-		return fmt.Errorf("failed to read migration file: %w", err)
+		return nil, err
 	}
 
-	_, err = db.Exec(string(migrationSQL))
+	applied, err := migrator.Up(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to execute migration: %w", err)
+		return nil, err
+	}
+	for _, migration := range applied {
+		slog.Info("applied migration", slog.Int64("version", migration.Version), slog.String("name", migration.Name))
 	}
 
-	return nil
+	if seed {
+		if err := migrator.Seed(ctx); err != nil {
+			return nil, fmt.Errorf("failed to seed sample data: %w", err)
+		}
+		slog.Info("seeded sample data")
+	}
+
+	return migrator, nil
+}
+
+// purgeDeletedHotels removes hotels deleted longer than retention ago, once at
+// startup and then every purgeInterval until ctx is done
+func purgeDeletedHotels(ctx context.Context, hotels service.HotelService, retention time.Duration) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := hotels.PurgeDeletedHotels(ctx, retention)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("failed to purge deleted hotels", slog.String("error", err.Error()))
+		} else if purged > 0 {
+			slog.Info("purged deleted hotels", slog.Int64("count", purged))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// purgeIdempotencyKeys forgets idempotency keys older than
+// service.IdempotencyKeyTTL, once at startup and then every purgeInterval until
+// ctx is done
+func purgeIdempotencyKeys(ctx context.Context, idempotency service.IdempotencyService) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := idempotency.PurgeExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("failed to purge idempotency keys", slog.String("error", err.Error()))
+		} else if purged > 0 {
+			slog.Info("purged idempotency keys", slog.Int64("count", purged))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"HotelService/infrastructure/config"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// serve runs the server until it fails or the process receives SIGINT or
+// SIGTERM. On a signal it closes draining, keeps serving for cfg.ShutdownDelay
+// so that load balancers see /readyz fail and stop sending traffic, then stops
+// accepting connections and waits up to cfg.ShutdownTimeout for in-flight
+// requests before returning.
+func serve(cfg config.Server, handler http.Handler, draining chan<- struct{}) error {
+	server := &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		slog.Info("listening", slog.String("addr", cfg.Addr()))
+		if cfg.TLSCertFile != "" {
+			errs <- server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			errs <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	// A second signal kills the process as usual
+	stop()
+	close(draining)
+	slog.Info("shutting down", slog.Duration("delay", cfg.ShutdownDelay))
+	time.Sleep(cfg.ShutdownDelay)
+
+	slog.Info("draining in-flight requests")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+
+	return nil
+}
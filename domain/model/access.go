@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// Role is what a hotelier is to a particular hotel. The owner holds RoleOwner
+// implicitly through Hotel.OwnerID; staff roles are assigned per hotel.
+type Role string
+
+const (
+	RoleOwner          Role = "owner"
+	RoleFrontDesk      Role = "front_desk"
+	RoleHousekeeping   Role = "housekeeping"
+	RoleRevenueManager Role = "revenue_manager"
+)
+
+// StaffRoles are the roles an owner may assign to other hoteliers
+var StaffRoles = []Role{RoleFrontDesk, RoleHousekeeping, RoleRevenueManager}
+
+func IsStaffRole(role Role) bool {
+	for _, r := range StaffRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Permission names one action on a hotel and everything that belongs to it.
+// Which roles hold which permission is stored in the role_permissions table.
+type Permission string
+
+const (
+	PermViewHotel        Permission = "hotel:view"
+	PermUpdateHotel      Permission = "hotel:update"
+	PermDeleteHotel      Permission = "hotel:delete"
+	PermManageRooms      Permission = "rooms:manage"
+	PermUpdateRooms      Permission = "rooms:update"
+	PermSetAvailability  Permission = "rooms:availability"
+	PermViewReservations Permission = "reservations:view"
+	PermViewRatePlans    Permission = "rate_plans:view"
+	PermManageRatePlans  Permission = "rate_plans:manage"
+	PermManageStaff      Permission = "staff:manage"
+	PermViewAudit        Permission = "audit:view"
+)
+
+// StaffMember is a hotelier holding a staff role at a hotel
+type StaffMember struct {
+	HotelID    int64     `json:"hotel_id"`
+	HotelierID int64     `json:"hotelier_id"`
+	Email      string    `json:"email"`
+	Name       string    `json:"name"`
+	Role       Role      `json:"role"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction is what a hotelier did to an audited entity
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDelete  AuditAction = "delete"
+	AuditRestore AuditAction = "restore"
+	AuditPurge   AuditAction = "purge"
+)
+
+// AuditEntity names the kind of entity an audit entry is about
+type AuditEntity string
+
+const (
+	AuditHotel        AuditEntity = "hotel"
+	AuditRoom         AuditEntity = "room"
+	AuditRatePlan     AuditEntity = "rate_plan"
+	AuditSeasonalRate AuditEntity = "seasonal_rate"
+	AuditStaff        AuditEntity = "staff"
+)
+
+// FieldChange holds the JSON value of one field before and after a change.
+// Before is empty for created entities and After for deleted ones.
+type FieldChange struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditEntry records one change a hotelier made at a hotel. ActorID is 0 for
+// the entry the purge job writes when it removes a deleted hotel for good.
+type AuditEntry struct {
+	ID         int64                  `json:"id"`
+	HotelID    int64                  `json:"hotel_id"`
+	ActorID    int64                  `json:"actor_id,omitempty"`
+	Action     AuditAction            `json:"action"`
+	EntityType AuditEntity            `json:"entity_type"`
+	EntityID   int64                  `json:"entity_id"`
+	Changes    map[string]FieldChange `json:"changes"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
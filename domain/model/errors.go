@@ -0,0 +1,107 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error kinds. Use errors.Is(err, ErrNotFound) and friends to classify any error
+// returned by the service or repositories.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrValidation = errors.New("validation failed")
+	ErrConflict   = errors.New("conflict")
+	ErrInternal   = errors.New("internal error")
+
+	// ErrPreconditionFailed means the caller changed an entity based on a
+	// version that is no longer current
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrUnprocessable means a well-formed request cannot be carried out as sent
+	ErrUnprocessable = errors.New("unprocessable")
+
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrForbidden       = errors.New("forbidden")
+)
+
+// ErrReservationOverlap is returned by storage when a confirmed reservation would
+// overlap another confirmed stay in the same room
+var ErrReservationOverlap = fmt.Errorf("reservation overlaps an existing stay: %w", ErrConflict)
+
+// Error is a classified domain error. Message is safe to show to API clients,
+// Err is the underlying cause and is only meant for logs.
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+func NewNotFoundError(format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewValidationError(format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrValidation, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewConflictError(format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewPreconditionFailedError(format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrPreconditionFailed, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewUnprocessableError(format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrUnprocessable, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewUnauthenticatedError(format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrUnauthenticated, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewForbiddenError(format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrForbidden, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewInternalError(err error, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrInternal, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// FieldError describes one invalid field of a request, e.g. "rooms[2].price"
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError carries every field error found in a request
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + " " + f.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// MaxIdempotencyKeyLength bounds the Idempotency-Key a client may send
+const MaxIdempotencyKeyLength = 255
+
+// IdempotentRequest remembers a create a hotelier sent with an Idempotency-Key
+// and the response it got, so that retries of it get the same response
+type IdempotentRequest struct {
+	HotelierID int64
+	Key        string
+	// RequestHash fingerprints the method, path and body of the request; a
+	// retry must match it
+	RequestHash string
+	// StatusCode is zero while the first request is still being processed
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+// Completed reports whether the response to the request is known
+func (r *IdempotentRequest) Completed() bool {
+	return r.StatusCode != 0
+}
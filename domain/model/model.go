@@ -2,13 +2,44 @@ package model
 
 import "time"
 
+// DefaultRoomCapacity is used when a room is created without an explicit guest capacity
+const DefaultRoomCapacity = 2
+
+// Maximum lengths of text fields, matching the VARCHAR columns in the schema
+const (
+	MaxHotelNameLength    = 255
+	MaxRoomNumberLength   = 50
+	MaxRoomTypeLength     = 100
+	MaxGuestNameLength    = 255
+	MaxGuestEmailLength   = 255
+	MaxRatePlanNameLength = 255
+	MaxHotelierNameLength = 255
+	MaxEmailLength        = 255
+)
+
 type Hotel struct {
 	ID        int64     `json:"id"`
 	Name      string    `json:"name"`
 	Address   string    `json:"address"`
+	Currency  string    `json:"currency"`
+	OwnerID   int64     `json:"owner_id,omitempty"`
 	Rooms     []Room    `json:"rooms,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Version grows with every change; it is the ETag of the hotel
+	Version int64 `json:"version"`
+	// DeletedAt is set while the hotel is soft-deleted and hidden from every listing
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Hotelier is an account that manages hotels through the /hotelier API
+type Hotelier struct {
+	ID           int64     `json:"id"`
+	Email        string    `json:"email"`
+	Name         string    `json:"name"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 type Room struct {
@@ -16,8 +47,40 @@ type Room struct {
 	HotelID   int64     `json:"hotel_id"`
 	Number    string    `json:"number"`
 	Type      string    `json:"type"`
-	Price     float64   `json:"price"`
+	Price     Money     `json:"price"`
+	Capacity  int       `json:"capacity"`
 	Available bool      `json:"available"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Version grows with every change; it is the ETag of the room
+	Version int64 `json:"version"`
+	// DeletedAt is set once the room is deleted; its reservations stay on record
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type ReservationStatus string
+
+const (
+	ReservationStatusConfirmed ReservationStatus = "confirmed"
+	ReservationStatusCancelled ReservationStatus = "cancelled"
+)
+
+type Reservation struct {
+	ID     int64 `json:"id"`
+	RoomID int64 `json:"room_id"`
+	// Code is the secret the guest presents to view or cancel the booking;
+	// empty for bookings made before codes were issued
+	Code       string            `json:"code,omitempty"`
+	GuestName  string            `json:"guest_name"`
+	GuestEmail string            `json:"guest_email"`
+	CheckIn    time.Time         `json:"check_in"`
+	CheckOut   time.Time         `json:"check_out"`
+	Status     ReservationStatus `json:"status"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// Nights returns the number of nights between check-in and check-out
+func (r *Reservation) Nights() int {
+	return int(r.CheckOut.Sub(r.CheckIn).Hours() / 24)
 }
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const DefaultCurrency = "USD"
+
+// currencyExponents lists supported ISO 4217 currencies and their number of minor
+// unit digits. Prices are stored as DECIMAL(10,2), so currencies with three
+// decimal places are not supported.
+var currencyExponents = map[string]int{
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"CHF": 2,
+	"CAD": 2,
+	"AUD": 2,
+	"RUB": 2,
+	"CNY": 2,
+	"INR": 2,
+	"TRY": 2,
+	"AED": 2,
+	"JPY": 0,
+	"KRW": 0,
+}
+
+// IsSupportedCurrency reports whether code is a supported ISO 4217 currency code
+func IsSupportedCurrency(code string) bool {
+	_, ok := currencyExponents[code]
+	return ok
+}
+
+// priceWholeDigits is the number of digits DECIMAL(10,2) keeps before the point
+const priceWholeDigits = 8
+
+// MaxPrice is the largest price the DECIMAL(10,2) columns hold in currency,
+// 99999999.99 for currencies with cents and 99999999 for those without
+func MaxPrice(currency string) Money {
+	limit := int64(1)
+	for i := 0; i < priceWholeDigits+currencyExponents[currency]; i++ {
+		limit *= 10
+	}
+	return Money{Amount: limit - 1, Currency: currency}
+}
+
+// Money is an exact amount in the minor units (e.g. cents) of an ISO 4217 currency.
+// It is serialized to JSON as {"amount": "150.00", "currency": "USD"}.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func NewMoney(minorUnits int64, currency string) Money {
+	return Money{Amount: minorUnits, Currency: currency}
+}
+
+// ParseMoney reads a decimal amount such as "150.5" in the given currency. Amounts
+// that cannot be represented exactly in the currency's minor units are rejected
+// rather than rounded; trailing zeros such as in "1000.00" JPY are fine.
+func ParseMoney(amount, currency string) (Money, error) {
+	exp, ok := currencyExponents[currency]
+	if !ok {
+		return Money{}, fmt.Errorf("unsupported currency %q", currency)
+	}
+
+	amount = strings.TrimSpace(amount)
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+
+	whole, frac, _ := strings.Cut(amount, ".")
+	if len(frac) > exp {
+		frac = strings.TrimRight(frac, "0")
+	}
+	if whole == "" && frac == "" {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	if len(frac) > exp {
+		return Money{}, fmt.Errorf("amount %q has more than %d decimal places for %s", amount, exp, currency)
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	digits := whole + frac
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return Money{}, fmt.Errorf("invalid amount %q", amount)
+		}
+	}
+
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q: %w", amount, err)
+	}
+	if negative {
+		minor = -minor
+	}
+
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+// Decimal formats the amount with the currency's number of decimal places, e.g. "150.00"
+func (m Money) Decimal() string {
+	exp := currencyExponents[m.Currency]
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if exp == 0 {
+		return sign + strconv.FormatInt(amount, 10)
+	}
+
+	s := fmt.Sprintf("%0*d", exp+1, amount)
+	return sign + s[:len(s)-exp] + "." + s[len(s)-exp:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// Add sums two amounts of the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("cannot add %s to %s", other.Currency, m.Currency)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Scale multiplies the amount by factor, which is taken to four decimal places as
+// stored in the database. The result is rounded to the nearest minor unit, halves
+// away from zero.
+func (m Money) Scale(factor float64) Money {
+	const precision = 10000
+	scaled := m.Amount * int64(math.Round(factor*precision))
+
+	result := scaled / precision
+	if rem := scaled % precision; rem*2 >= precision {
+		result++
+	} else if rem*2 <= -precision {
+		result--
+	}
+
+	return Money{Amount: result, Currency: m.Currency}
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Decimal(), m.Currency})
+}
+
+// UnmarshalJSON accepts the amount either as a string or as a JSON number
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Amount   json.RawMessage `json:"amount"`
+		Currency string          `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	amount := string(bytes.Trim(raw.Amount, `"`))
+	parsed, err := ParseMoney(amount, strings.ToUpper(raw.Currency))
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,150 @@
+package model_test
+
+import (
+	"HotelService/domain/model"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{amount: "150", currency: "USD", want: 15000},
+		{amount: "150.5", currency: "USD", want: 15050},
+		{amount: " 150.05 ", currency: "EUR", want: 15005},
+		{amount: ".5", currency: "USD", want: 50},
+		{amount: "12.340", currency: "USD", want: 1234},
+		{amount: "-5.25", currency: "USD", want: -525},
+		{amount: "1000", currency: "JPY", want: 1000},
+		{amount: "1000.00", currency: "JPY", want: 1000},
+		{amount: "50000", currency: "KRW", want: 50000},
+
+		// over-precise amounts are rejected rather than rounded
+		{amount: "12.345", currency: "USD", wantErr: true},
+		{amount: "1000.5", currency: "JPY", wantErr: true},
+		{amount: "0.01", currency: "KRW", wantErr: true},
+
+		// malformed amounts
+		{amount: "", currency: "USD", wantErr: true},
+		{amount: ".", currency: "USD", wantErr: true},
+		{amount: "-", currency: "USD", wantErr: true},
+		{amount: "abc", currency: "USD", wantErr: true},
+		{amount: "1.2.3", currency: "USD", wantErr: true},
+		{amount: "1e3", currency: "USD", wantErr: true},
+		{amount: "+5", currency: "USD", wantErr: true},
+		{amount: "--5", currency: "USD", wantErr: true},
+		{amount: "1 000", currency: "USD", wantErr: true},
+		{amount: "99999999999999999999", currency: "USD", wantErr: true},
+		{amount: "10", currency: "XYZ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := model.ParseMoney(tt.amount, tt.currency)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q, %s) = %v, want an error", tt.amount, tt.currency, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMoney(%q, %s): %v", tt.amount, tt.currency, err)
+			continue
+		}
+		if got != model.NewMoney(tt.want, tt.currency) {
+			t.Errorf("ParseMoney(%q, %s) = %d, want %d", tt.amount, tt.currency, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	tests := []struct {
+		money model.Money
+		want  string
+	}{
+		{model.NewMoney(15000, "USD"), "150.00"},
+		{model.NewMoney(5, "USD"), "0.05"},
+		{model.NewMoney(-525, "EUR"), "-5.25"},
+		{model.NewMoney(0, "GBP"), "0.00"},
+		{model.NewMoney(1000, "JPY"), "1000"},
+		{model.NewMoney(-50000, "KRW"), "-50000"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Decimal(); got != tt.want {
+			t.Errorf("Decimal of %d %s = %q, want %q", tt.money.Amount, tt.money.Currency, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyScale(t *testing.T) {
+	tests := []struct {
+		money  model.Money
+		factor float64
+		want   int64
+	}{
+		{model.NewMoney(10000, "USD"), 1, 10000},
+		{model.NewMoney(10000, "USD"), 1.25, 12500},
+		{model.NewMoney(1001, "USD"), 0.5, 501},
+		{model.NewMoney(1003, "USD"), 0.5, 502},
+		{model.NewMoney(1001, "USD"), 1.5, 1502},
+		{model.NewMoney(1004, "USD"), 0.3333, 335},
+		{model.NewMoney(1005, "USD"), 0.3333, 335},
+
+		// halves go away from zero on both sides
+		{model.NewMoney(1, "USD"), 0.5, 1},
+		{model.NewMoney(-1, "USD"), 0.5, -1},
+		{model.NewMoney(-1001, "USD"), 1.5, -1502},
+		{model.NewMoney(-1003, "USD"), 0.5, -502},
+
+		// the factor is taken to four decimal places first
+		{model.NewMoney(10000, "USD"), 1.23454, 12345},
+		{model.NewMoney(1, "USD"), 0.49995, 1},
+
+		// currencies without minor units round to whole units
+		{model.NewMoney(999, "JPY"), 1.25, 1249},
+		{model.NewMoney(1001, "KRW"), 0.5, 501},
+		{model.NewMoney(1000, "JPY"), 0.3333, 333},
+	}
+
+	for _, tt := range tests {
+		got := tt.money.Scale(tt.factor)
+		if got != model.NewMoney(tt.want, tt.money.Currency) {
+			t.Errorf("%d %s scaled by %v = %d %s, want %d", tt.money.Amount, tt.money.Currency, tt.factor, got.Amount, got.Currency, tt.want)
+		}
+	}
+}
+
+func TestMoneyAdd(t *testing.T) {
+	sum, err := model.NewMoney(1050, "USD").Add(model.NewMoney(-75, "USD"))
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if sum != model.NewMoney(975, "USD") {
+		t.Errorf("10.50 + -0.75 USD = %s, want 9.75 USD", sum)
+	}
+
+	if _, err := model.NewMoney(100, "USD").Add(model.NewMoney(100, "JPY")); err == nil {
+		t.Error("Add of USD and JPY succeeded, want an error")
+	}
+}
+
+func TestMaxPrice(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     string
+	}{
+		{"USD", "99999999.99"},
+		{"EUR", "99999999.99"},
+		{"JPY", "99999999"},
+		{"KRW", "99999999"},
+	}
+
+	for _, tt := range tests {
+		if got := model.MaxPrice(tt.currency).Decimal(); got != tt.want {
+			t.Errorf("MaxPrice(%s) = %s, want %s", tt.currency, got, tt.want)
+		}
+	}
+}
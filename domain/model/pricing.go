@@ -0,0 +1,118 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// RatePlan is a named set of prices a hotel sells its rooms under, e.g. "Standard" or "Non-refundable"
+type RatePlan struct {
+	ID            int64          `json:"id"`
+	HotelID       int64          `json:"hotel_id"`
+	Name          string         `json:"name"`
+	SeasonalRates []SeasonalRate `json:"seasonal_rates,omitempty"`
+	DayModifiers  []DayModifier  `json:"day_modifiers,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// SeasonalRate overrides the nightly price for nights in [StartDate, EndDate).
+// It applies to a single room when RoomID is set, to every room of RoomType when
+// that is set, and to all rooms of the hotel otherwise.
+type SeasonalRate struct {
+	ID         int64     `json:"id"`
+	RatePlanID int64     `json:"rate_plan_id"`
+	RoomID     *int64    `json:"room_id,omitempty"`
+	RoomType   string    `json:"room_type,omitempty"`
+	StartDate  time.Time `json:"start_date"`
+	EndDate    time.Time `json:"end_date"`
+	Price      Money     `json:"price"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// DayModifier scales the nightly price for one day of the week, e.g. 1.2 on Saturdays
+type DayModifier struct {
+	Weekday    time.Weekday `json:"weekday"`
+	Multiplier float64      `json:"multiplier"`
+}
+
+// MaxMultiplier is the exclusive bound DECIMAL(6,4) puts on a day modifier
+const MaxMultiplier = 100
+
+// RoundMultiplier takes a multiplier to the four decimal places it is stored
+// with and reports whether that changed its value
+func RoundMultiplier(multiplier float64) (float64, bool) {
+	const precision = 10000
+	rounded := math.Round(multiplier*precision) / precision
+	return rounded, math.Abs(rounded-multiplier) > 1e-9
+}
+
+type NightlyPrice struct {
+	Date           time.Time `json:"date"`
+	BasePrice      Money     `json:"base_price"`
+	SeasonalRateID *int64    `json:"seasonal_rate_id,omitempty"`
+	Multiplier     float64   `json:"multiplier"`
+	Price          Money     `json:"price"`
+}
+
+// PriceQuote is the price breakdown of a stay, one entry per night
+type PriceQuote struct {
+	RoomID     int64          `json:"room_id"`
+	RatePlanID int64          `json:"rate_plan_id,omitempty"`
+	CheckIn    time.Time      `json:"check_in"`
+	CheckOut   time.Time      `json:"check_out"`
+	Nights     []NightlyPrice `json:"nights"`
+	Total      Money          `json:"total"`
+}
+
+func (r *SeasonalRate) covers(night time.Time) bool {
+	return !night.Before(r.StartDate) && night.Before(r.EndDate)
+}
+
+// specificity ranks how closely the rate targets room: room-specific rates beat
+// room-type rates, which beat hotel-wide rates. -1 means the rate does not apply.
+func (r *SeasonalRate) specificity(room *Room) int {
+	switch {
+	case r.RoomID != nil:
+		if *r.RoomID == room.ID {
+			return 2
+		}
+		return -1
+	case r.RoomType != "":
+		if r.RoomType == room.Type {
+			return 1
+		}
+		return -1
+	default:
+		return 0
+	}
+}
+
+// NightlyPrice resolves the price of room for one night under the plan. The most
+// specific seasonal rate wins, ties going to the most recently added one; without
+// one the room's own price is used. The day-of-week modifier is applied on top.
+func (p *RatePlan) NightlyPrice(room *Room, night time.Time) NightlyPrice {
+	np := NightlyPrice{Date: night, BasePrice: room.Price, Multiplier: 1}
+
+	best := -1
+	for i := range p.SeasonalRates {
+		rate := &p.SeasonalRates[i]
+		if !rate.covers(night) {
+			continue
+		}
+		if spec := rate.specificity(room); spec >= 0 && spec >= best {
+			best = spec
+			np.BasePrice = rate.Price
+			np.SeasonalRateID = &rate.ID
+		}
+	}
+
+	for _, m := range p.DayModifiers {
+		if m.Weekday == night.Weekday() {
+			np.Multiplier = m.Multiplier
+		}
+	}
+
+	np.Price = np.BasePrice.Scale(np.Multiplier)
+	return np
+}
@@ -0,0 +1,131 @@
+package model_test
+
+import (
+	"HotelService/domain/model"
+	"testing"
+	"time"
+)
+
+func TestRatePlanNightlyPrice(t *testing.T) {
+	otherRoom := int64(2)
+	plan := &model.RatePlan{
+		SeasonalRates: []model.SeasonalRate{
+			{ID: 1, StartDate: date(2030, 7, 1), EndDate: date(2030, 7, 3), Price: model.NewMoney(15000, "USD")},
+			{ID: 2, RoomType: "Double", StartDate: date(2030, 7, 1), EndDate: date(2030, 7, 2), Price: model.NewMoney(16000, "USD")},
+			{ID: 3, RoomID: &otherRoom, StartDate: date(2030, 7, 1), EndDate: date(2030, 7, 3), Price: model.NewMoney(17000, "USD")},
+		},
+		DayModifiers: []model.DayModifier{{Weekday: time.Saturday, Multiplier: 1.25}},
+	}
+
+	tests := []struct {
+		name     string
+		plan     *model.RatePlan
+		room     *model.Room
+		checkIn  time.Time
+		checkOut time.Time
+		want     []int64
+		total    int64
+	}{
+		{
+			name:     "without a plan",
+			plan:     &model.RatePlan{},
+			room:     &model.Room{ID: 1, Type: "Double", Price: model.NewMoney(10000, "USD")},
+			checkIn:  date(2030, 6, 28),
+			checkOut: date(2030, 7, 1),
+			want:     []int64{10000, 10000, 10000},
+			total:    30000,
+		},
+		{
+			name:     "Saturday and seasonal rates",
+			plan:     plan,
+			room:     &model.Room{ID: 1, Type: "Double", Price: model.NewMoney(10000, "USD")},
+			checkIn:  date(2030, 6, 28),
+			checkOut: date(2030, 7, 3),
+			// Fri, Sat x1.25, Sun, Mon at the Double rate, Tue at the hotel-wide rate
+			want:  []int64{10000, 12500, 10000, 16000, 15000},
+			total: 63500,
+		},
+		{
+			name:     "rate of another room type",
+			plan:     plan,
+			room:     &model.Room{ID: 1, Type: "Single", Price: model.NewMoney(8000, "USD")},
+			checkIn:  date(2030, 6, 30),
+			checkOut: date(2030, 7, 3),
+			want:     []int64{8000, 15000, 15000},
+			total:    38000,
+		},
+		{
+			name:     "room-specific rate",
+			plan:     plan,
+			room:     &model.Room{ID: otherRoom, Type: "Double", Price: model.NewMoney(10000, "USD")},
+			checkIn:  date(2030, 7, 1),
+			checkOut: date(2030, 7, 3),
+			want:     []int64{17000, 17000},
+			total:    34000,
+		},
+		{
+			name:     "rounded Saturday in yen",
+			plan:     plan,
+			room:     &model.Room{ID: 1, Type: "Double", Price: model.NewMoney(9999, "JPY")},
+			checkIn:  date(2030, 6, 28),
+			checkOut: date(2030, 6, 30),
+			want:     []int64{9999, 12499},
+			total:    22498,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total := model.NewMoney(0, tt.room.Price.Currency)
+			var got []int64
+			for night := tt.checkIn; night.Before(tt.checkOut); night = night.AddDate(0, 0, 1) {
+				price := tt.plan.NightlyPrice(tt.room, night)
+				got = append(got, price.Price.Amount)
+
+				var err error
+				if total, err = total.Add(price.Price); err != nil {
+					t.Fatalf("Add: %v", err)
+				}
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("nights = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("night %d = %d, want %d", i, got[i], tt.want[i])
+				}
+			}
+			if total.Amount != tt.total {
+				t.Errorf("total = %s, want %d", total, tt.total)
+			}
+		})
+	}
+}
+
+func TestRoundMultiplier(t *testing.T) {
+	tests := []struct {
+		multiplier  float64
+		want        float64
+		wantInexact bool
+	}{
+		{1, 1, false},
+		{1.1, 1.1, false},
+		{1.2345, 1.2345, false},
+		{99.9999, 99.9999, false},
+		{1.23456, 1.2346, true},
+		{0.00004, 0, true},
+		{99.99995, 100, true},
+	}
+
+	for _, tt := range tests {
+		got, inexact := model.RoundMultiplier(tt.multiplier)
+		if got != tt.want || inexact != tt.wantInexact {
+			t.Errorf("RoundMultiplier(%v) = %v, %v, want %v, %v", tt.multiplier, got, inexact, tt.want, tt.wantInexact)
+		}
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -3,18 +3,25 @@ package main
 import (
 	"HotelService/application/dto"
 	"HotelService/application/service"
+	"HotelService/domain/model"
+	"HotelService/infrastructure/auth"
+	"HotelService/infrastructure/config"
 	"HotelService/infrastructure/db"
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"time"
 )
 
 func main() {
 	// 1. Setup database connection
-	dbConfig := db.DefaultConfig()
+	cfg, err := config.LoadStorage(config.StoragePostgres, os.LookupEnv)
+	if err != nil {
+		log.Fatal("Invalid configuration:", err)
+	}
 
-	database, err := db.NewPostgresDB(dbConfig)
+	database, err := db.NewPostgresDB(cfg.PostgresConfig())
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
@@ -23,13 +30,21 @@ func main() {
 	fmt.Println("✓ Connected to database successfully")
 
 	// 2. Initialize repositories
+	uow := db.NewUnitOfWork(database)
 	hotelRepo := db.NewHotelRepository(database)
 	roomRepo := db.NewRoomRepository(database)
+	reservationRepo := db.NewReservationRepository(database)
+	ratePlanRepo := db.NewRatePlanRepository(database)
+	hotelierRepo := db.NewHotelierRepository(database)
+	staffRepo := db.NewStaffRepository(database)
+	auditRepo := db.NewAuditRepository(database)
 
 	fmt.Println("✓ Repositories initialized")
 
 	// 3. Initialize hotel service
-	hotelService := service.NewHotelService(hotelRepo, roomRepo)
+	hotelService := service.NewHotelService(uow, hotelRepo, roomRepo, reservationRepo, ratePlanRepo, staffRepo, auditRepo)
+
+	authService := service.NewAuthService(hotelierRepo, auth.NewBcryptHasher(), auth.NewJWTIssuer([]byte("example-secret"), time.Hour))
 
 	fmt.Println("✓ Hotel service initialized")
 
@@ -37,20 +52,37 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Register a hotelier; hotel management calls act on their behalf
+	fmt.Println("\n--- Registering a hotelier ---")
+	email := fmt.Sprintf("owner+%d@example.com", time.Now().Unix())
+	hotelier, err := authService.Register(ctx, email, "correct horse battery", "Example Owner")
+	if err != nil {
+		log.Fatal("Error registering hotelier:", err)
+	}
+	fmt.Printf("✓ Registered hotelier: ID=%d, Email=%s\n", hotelier.ID, hotelier.Email)
+
+	token, err := authService.Login(ctx, email, "correct horse battery")
+	if err != nil {
+		log.Fatal("Error logging in:", err)
+	}
+	fmt.Printf("✓ Logged in, token expires at %s\n", token.ExpiresAt.Format(time.RFC3339))
+
+	ctx = service.WithHotelier(ctx, hotelier.ID)
+
 	// 5. Example: Create a new hotel with rooms
 	fmt.Println("\n--- Creating a new hotel ---")
 	rooms := []dto.RoomInput{
-		{Number: "301", Type: "Suite", Price: 300.00, Available: true},
-		{Number: "302", Type: "Double", Price: 200.00, Available: true},
-		{Number: "303", Type: "Single", Price: 150.00, Available: false},
+		{Number: "301", Type: "Suite", Price: model.NewMoney(30000, "USD"), Capacity: 4, Available: true},
+		{Number: "302", Type: "Double", Price: model.NewMoney(20000, "USD"), Capacity: 2, Available: true},
+		{Number: "303", Type: "Single", Price: model.NewMoney(15000, "USD"), Capacity: 1, Available: false},
 	}
-	hotel, err := hotelService.CreateHotel(ctx, "Luxury Hotel", "789 Park Ave, Seattle, WA", rooms)
+	hotel, err := hotelService.CreateHotel(ctx, "Luxury Hotel", "789 Park Ave, Seattle, WA", "USD", rooms)
 	if err != nil {
 		log.Printf("Error creating hotel: %v", err)
 	} else {
 		fmt.Printf("✓ Created hotel: ID=%d, Name=%s, Rooms=%d\n", hotel.ID, hotel.Name, len(hotel.Rooms))
 		for _, room := range hotel.Rooms {
-			fmt.Printf("  - Room %s: %s ($%.2f, Available: %v)\n",
+			fmt.Printf("  - Room %s: %s (%s, Available: %v)\n",
 				room.Number, room.Type, room.Price, room.Available)
 		}
 	}
@@ -73,41 +105,47 @@ func main() {
 
 	// 7. Example: List all hotels
 	fmt.Println("\n--- Listing all hotels ---")
-	hotels, err := hotelService.ListHotels(ctx)
+	hotels, err := hotelService.ListHotels(ctx, dto.HotelFilter{})
 	if err != nil {
 		log.Printf("Error listing hotels: %v", err)
 	} else {
-		fmt.Printf("✓ Found %d hotels:\n", len(hotels))
-		for _, h := range hotels {
+		fmt.Printf("✓ Found %d hotels:\n", len(hotels.Items))
+		for _, h := range hotels.Items {
 			fmt.Printf("  - ID=%d, Name=%s\n", h.ID, h.Name)
 		}
 	}
 
 	// 8. Example: Find available rooms
 	fmt.Println("\n--- Finding available rooms ---")
-	availableRooms, err := hotelService.FindAvailableRooms(ctx)
+	availableRooms, err := hotelService.FindAvailableRooms(ctx, dto.StaySearch{
+		CheckIn:  time.Now().AddDate(0, 0, 1),
+		CheckOut: time.Now().AddDate(0, 0, 3),
+		Guests:   2,
+	})
 	if err != nil {
 		log.Printf("Error finding available rooms: %v", err)
 	} else {
-		fmt.Printf("✓ Found %d available rooms:\n", len(availableRooms))
-		for _, room := range availableRooms {
+		fmt.Printf("✓ Found %d available rooms:\n", len(availableRooms.Items))
+		for _, room := range availableRooms.Items {
 			fmt.Printf("  - Room %s (Hotel ID: %d)\n", room.Number, room.HotelID)
 		}
 	}
 
 	// 9. Example: Update room availability
 	fmt.Println("\n--- Updating room availability ---")
-	if len(availableRooms) > 0 {
-		roomID := availableRooms[0].ID
-		err = hotelService.UpdateRoomAvailability(ctx, roomID, false)
+	if availableRooms != nil && len(availableRooms.Items) > 0 {
+		roomID := availableRooms.Items[0].ID
+		version := availableRooms.Items[0].Version
+		room, err := hotelService.UpdateRoomAvailability(ctx, roomID, version, false)
 		if err != nil {
 			log.Printf("Error updating room availability: %v", err)
 		} else {
 			fmt.Printf("✓ Room %d marked as unavailable\n", roomID)
+			version = room.Version
 		}
 
-		// Mark it back as available
-		err = hotelService.UpdateRoomAvailability(ctx, roomID, true)
+		// Mark it back as available at the version the first update returned
+		_, err = hotelService.UpdateRoomAvailability(ctx, roomID, version, true)
 		if err != nil {
 			log.Printf("Error updating room availability: %v", err)
 		} else {
@@ -118,7 +156,7 @@ func main() {
 	// 10. Example: Update hotel information (Hotelier operation)
 	fmt.Println("\n--- Updating hotel information ---")
 	if hotel != nil {
-		updatedHotel, err := hotelService.UpdateHotel(ctx, hotel.ID, "Updated Luxury Hotel", "999 Updated Ave, Seattle, WA")
+		updatedHotel, err := hotelService.UpdateHotel(ctx, hotel.ID, hotel.Version, "Updated Luxury Hotel", "999 Updated Ave, Seattle, WA")
 		if err != nil {
 			log.Printf("Error updating hotel: %v", err)
 		} else {
@@ -130,11 +168,11 @@ func main() {
 	// 11. Example: Add a new room to hotel (Hotelier operation)
 	fmt.Println("\n--- Adding a new room to hotel ---")
 	if hotel != nil {
-		newRoom, err := hotelService.AddRoomToHotel(ctx, hotel.ID, "304", "Deluxe", 250.00, true)
+		newRoom, err := hotelService.AddRoomToHotel(ctx, hotel.ID, "304", "Deluxe", model.NewMoney(25000, "USD"), 2, true)
 		if err != nil {
 			log.Printf("Error adding room: %v", err)
 		} else {
-			fmt.Printf("✓ Added room: ID=%d, Number=%s, Type=%s, Price=$%.2f\n",
+			fmt.Printf("✓ Added room: ID=%d, Number=%s, Type=%s, Price=%s\n",
 				newRoom.ID, newRoom.Number, newRoom.Type, newRoom.Price)
 		}
 	}
@@ -146,28 +184,123 @@ func main() {
 		updatedHotel, err := hotelService.GetHotel(ctx, hotel.ID)
 		if err == nil && len(updatedHotel.Rooms) > 0 {
 			roomToUpdate := updatedHotel.Rooms[len(updatedHotel.Rooms)-1] // Last room (newly added)
-			updatedRoom, err := hotelService.UpdateRoom(ctx, roomToUpdate.ID, "304", "Premium Suite", 350.00, true)
+			updatedRoom, err := hotelService.UpdateRoom(ctx, roomToUpdate.ID, roomToUpdate.Version, "304", "Premium Suite", model.NewMoney(35000, "USD"), 3, true)
 			if err != nil {
 				log.Printf("Error updating room: %v", err)
 			} else {
-				fmt.Printf("✓ Updated room: ID=%d, Number=%s, Type=%s, Price=$%.2f\n",
+				fmt.Printf("✓ Updated room: ID=%d, Number=%s, Type=%s, Price=%s\n",
 					updatedRoom.ID, updatedRoom.Number, updatedRoom.Type, updatedRoom.Price)
 			}
 		}
 	}
 
+	// 13. Example: Book a room for a stay and cancel it (Client operation)
+	fmt.Println("\n--- Booking a room ---")
+	if hotel != nil && len(hotel.Rooms) > 0 {
+		checkIn := time.Now().AddDate(0, 0, 7)
+		checkOut := checkIn.AddDate(0, 0, 3)
+		reservation, err := hotelService.CreateReservation(ctx, hotel.Rooms[0].ID, "Jane Doe", "jane@example.com", checkIn, checkOut)
+		if err != nil {
+			log.Printf("Error creating reservation: %v", err)
+		} else {
+			fmt.Printf("✓ Created reservation: ID=%d, Code=%s, Room ID=%d, Nights=%d\n",
+				reservation.ID, reservation.Code, reservation.RoomID, reservation.Nights())
+
+			// The guest presents the code they were given to manage the booking
+			if err := hotelService.CancelReservation(ctx, reservation.ID, dto.ReservationAccess{Code: reservation.Code}); err != nil {
+				log.Printf("Error cancelling reservation: %v", err)
+			} else {
+				fmt.Printf("✓ Reservation %d cancelled\n", reservation.ID)
+			}
+		}
+	}
+
+	// 14. Example: Weekend pricing with a rate plan (Hotelier + Client operation)
+	fmt.Println("\n--- Pricing a stay ---")
+	if hotel != nil && len(hotel.Rooms) > 0 {
+		plan, err := hotelService.CreateRatePlan(ctx, hotel.ID, "Standard")
+		if err != nil {
+			log.Printf("Error creating rate plan: %v", err)
+		} else {
+			if err := hotelService.SetDayModifier(ctx, plan.ID, time.Saturday, 1.25); err != nil {
+				log.Printf("Error setting weekend modifier: %v", err)
+			}
+
+			checkIn := time.Now().AddDate(0, 0, 14)
+			quote, err := hotelService.QuoteStay(ctx, hotel.Rooms[0].ID, plan.ID, checkIn, checkIn.AddDate(0, 0, 7))
+			if err != nil {
+				log.Printf("Error quoting stay: %v", err)
+			} else {
+				fmt.Printf("✓ %d nights in room %s cost %s\n", len(quote.Nights), hotel.Rooms[0].Number, quote.Total)
+			}
+		}
+	}
+
+	// 15. Example: Front desk staff may toggle availability but not change prices (Hotelier operation)
+	fmt.Println("\n--- Staff roles ---")
+	if hotel != nil && len(hotel.Rooms) > 0 {
+		accessService := service.NewAccessService(uow, hotelRepo, roomRepo, ratePlanRepo, hotelierRepo, staffRepo, auditRepo)
+
+		staffEmail := fmt.Sprintf("desk+%d@example.com", time.Now().Unix())
+		clerk, err := authService.Register(ctx, staffEmail, "front desk password", "Front Desk")
+		if err != nil {
+			log.Printf("Error registering staff: %v", err)
+		} else if _, err := accessService.AssignStaff(ctx, hotel.ID, staffEmail, model.RoleFrontDesk); err != nil {
+			log.Printf("Error assigning staff role: %v", err)
+		} else {
+			clerkCtx := service.WithHotelier(ctx, clerk.ID)
+			room := hotel.Rooms[0]
+
+			if _, err := hotelService.UpdateRoomAvailability(clerkCtx, room.ID, service.AnyVersion, room.Available); err != nil {
+				log.Printf("Error updating availability as front desk: %v", err)
+			} else {
+				fmt.Printf("✓ Front desk updated availability of room %s\n", room.Number)
+			}
+
+			_, err := hotelService.UpdateRoom(clerkCtx, room.ID, service.AnyVersion, room.Number, room.Type, model.NewMoney(1000, "USD"), room.Capacity, room.Available)
+			fmt.Printf("✓ Front desk price change rejected: %v\n", err)
+		}
+	}
+
 	fmt.Println("\n✓ All examples completed successfully!")
 	fmt.Println("\n--- API Endpoints Summary ---")
-	fmt.Println("Hotelier Endpoints:")
+	fmt.Println("Hotelier Endpoints (Authorization: Bearer <token>):")
+	fmt.Println("  POST   /hotelier/register                  - Create hotelier account")
+	fmt.Println("  POST   /hotelier/login                     - Obtain access token")
 	fmt.Println("  POST   /hotelier/hotels                    - Create hotel")
 	fmt.Println("  PUT    /hotelier/hotels/{id}               - Update hotel")
+	fmt.Println("  PATCH  /hotelier/hotels/{id}               - Update some hotel fields (merge patch)")
 	fmt.Println("  GET    /hotelier/hotels/{id}               - Get hotel details")
+	fmt.Println("  DELETE /hotelier/hotels/{id}               - Delete hotel")
+	fmt.Println("  POST   /hotelier/hotels/{id}/restore       - Restore deleted hotel")
+	fmt.Println("  GET    /hotelier/hotels/{id}/audit         - List audit log")
 	fmt.Println("  POST   /hotelier/hotels/{id}/rooms         - Add room to hotel")
+	fmt.Println("  GET    /hotelier/rooms/{id}                - Get room details")
 	fmt.Println("  PUT    /hotelier/rooms/{id}                - Update room")
+	fmt.Println("  PATCH  /hotelier/rooms/{id}                - Update some room fields (merge patch)")
 	fmt.Println("  DELETE /hotelier/rooms/{id}                - Delete room")
 	fmt.Println("  PATCH  /hotelier/rooms/{id}/availability   - Update room availability")
+	fmt.Println("  GET    /hotelier/rooms/{id}/reservations   - List room reservations")
+	fmt.Println("  POST   /hotelier/hotels/{id}/staff         - Assign staff role (front_desk, housekeeping, revenue_manager)")
+	fmt.Println("  GET    /hotelier/hotels/{id}/staff         - List staff")
+	fmt.Println("  DELETE /hotelier/hotels/{id}/staff/{hotelierId} - Remove staff member")
+	fmt.Println("  POST   /hotelier/hotels/{id}/rate-plans    - Create rate plan")
+	fmt.Println("  GET    /hotelier/hotels/{id}/rate-plans    - List rate plans")
+	fmt.Println("  GET    /hotelier/rate-plans/{id}           - Get rate plan")
+	fmt.Println("  POST   /hotelier/rate-plans/{id}/seasons   - Add seasonal rate")
+	fmt.Println("  PUT    /hotelier/rate-plans/{id}/day-modifiers - Set day-of-week modifier")
+	fmt.Println("  PUT, PATCH and DELETE of hotels and rooms need If-Match with the ETag or version from GET /hotelier/hotels/{id} or /hotelier/rooms/{id}")
+	fmt.Println("  POST of hotels and rooms accepts an Idempotency-Key header; retries replay the first response")
 	fmt.Println("\nClient Endpoints:")
-	fmt.Println("  GET    /client/hotels                      - List all hotels")
+	fmt.Println("  GET    /client/hotels                      - List hotels (name, address, sort, limit, cursor)")
 	fmt.Println("  GET    /client/hotels/{id}                 - Get hotel details")
-	fmt.Println("  GET    /client/rooms/available             - Find available rooms")
+	fmt.Println("  GET    /client/rooms/available             - Find available rooms (check_in, check_out, guests, hotel_id, type, min_price, max_price, sort, limit, cursor)")
+	fmt.Println("  GET    /client/rooms/{id}/quote            - Price a stay (check_in, check_out, rate_plan_id)")
+	fmt.Println("  POST   /client/reservations                - Book a room")
+	fmt.Println("  GET    /client/reservations/{id}           - Get reservation (code or guest_email)")
+	fmt.Println("  DELETE /client/reservations/{id}           - Cancel reservation (code or guest_email)")
+	fmt.Println("\nProbe Endpoints:")
+	fmt.Println("  GET    /healthz                            - Liveness")
+	fmt.Println("  GET    /readyz                             - Readiness (database, migrations, shutdown)")
+	fmt.Println("  GET    /version                            - Build commit, time and schema version")
 }
@@ -0,0 +1,165 @@
+// Package config loads the settings of the server and of the tools that work on
+// its database. Every setting has a default and can be overridden, in order of
+// increasing precedence, by a YAML or JSON file, an environment variable and a
+// command-line flag.
+package config
+
+import (
+	"HotelService/application/service"
+	"HotelService/infrastructure/db"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Storage backends
+const (
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+	StorageMemory   = "memory"
+)
+
+type Config struct {
+	// Storage is the backend: "postgres", "sqlite", or "memory" for a demo
+	// that keeps nothing
+	Storage  string   `yaml:"storage"`
+	Server   Server   `yaml:"server"`
+	Auth     Auth     `yaml:"auth"`
+	Postgres Postgres `yaml:"postgres"`
+	SQLite   SQLite   `yaml:"sqlite"`
+
+	// DeletedHotelRetention is how long a deleted hotel can be restored before
+	// it is purged
+	DeletedHotelRetention time.Duration `yaml:"deleted_hotel_retention"`
+
+	// SeedSampleData loads the sample hotels after migrating
+	SeedSampleData bool `yaml:"seed_sample_data"`
+}
+
+type Server struct {
+	// BindAddress is the host to listen on; empty binds every interface
+	BindAddress string `yaml:"bind_address"`
+	Port        int    `yaml:"port"`
+
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+
+	// ShutdownDelay is how long the server keeps serving after SIGTERM with
+	// /readyz failing, and ShutdownTimeout how long in-flight requests then
+	// get to finish
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	// RequestTimeout is the deadline each request gets to finish its work
+	RequestTimeout     time.Duration `yaml:"request_timeout"`
+	MaxRequestBodySize int64         `yaml:"max_request_body_size"`
+
+	// TLSCertFile and TLSKeyFile switch the server to HTTPS when both are set
+	TLSCertFile string `yaml:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file"`
+}
+
+// Addr is the host:port the server listens on
+func (s Server) Addr() string {
+	return net.JoinHostPort(s.BindAddress, strconv.Itoa(s.Port))
+}
+
+type Auth struct {
+	// TokenSecret signs the hotelier access tokens. TokenSecretFile names a
+	// file to read it from instead, e.g. a mounted container secret.
+	TokenSecret     string        `yaml:"token_secret"`
+	TokenSecretFile string        `yaml:"token_secret_file"`
+	TokenTTL        time.Duration `yaml:"token_ttl"`
+}
+
+type Postgres struct {
+	Host   string `yaml:"host"`
+	Port   int    `yaml:"port"`
+	User   string `yaml:"user"`
+	DBName string `yaml:"dbname"`
+
+	// Password may be read from PasswordFile instead
+	Password     string `yaml:"password"`
+	PasswordFile string `yaml:"password_file"`
+
+	SSLMode string `yaml:"sslmode"`
+
+	// MaxOpenConns of 0 leaves the pool unbounded
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	ConnectTimeout  time.Duration `yaml:"connect_timeout"`
+}
+
+type SQLite struct {
+	// Path is the database file; ":memory:" keeps the database in memory
+	Path string `yaml:"path"`
+
+	// BusyTimeout is how long a query waits for a lock held by another process
+	BusyTimeout time.Duration `yaml:"busy_timeout"`
+}
+
+// Default returns the settings used for everything left unset
+func Default() Config {
+	return Config{
+		Storage: StoragePostgres,
+		Server: Server{
+			Port:              8080,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			// Writes get a little longer than handlers so that a timed-out
+			// request can still send its error
+			WriteTimeout:       35 * time.Second,
+			IdleTimeout:        60 * time.Second,
+			ShutdownDelay:      5 * time.Second,
+			ShutdownTimeout:    20 * time.Second,
+			RequestTimeout:     30 * time.Second,
+			MaxRequestBodySize: 1 << 20,
+		},
+		Auth: Auth{
+			TokenTTL: 24 * time.Hour,
+		},
+		Postgres: Postgres{
+			Port:            5432,
+			SSLMode:         "require",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 5 * time.Minute,
+			ConnMaxIdleTime: 10 * time.Minute,
+			ConnectTimeout:  5 * time.Second,
+		},
+		SQLite: SQLite{
+			Path:        "hotel.db",
+			BusyTimeout: 5 * time.Second,
+		},
+		DeletedHotelRetention: service.DefaultDeletedHotelRetention,
+	}
+}
+
+// PostgresConfig is the connection configuration for db.NewPostgresDB
+func (c *Config) PostgresConfig() db.Config {
+	return db.Config{
+		Host:            c.Postgres.Host,
+		Port:            c.Postgres.Port,
+		User:            c.Postgres.User,
+		Password:        c.Postgres.Password,
+		DBName:          c.Postgres.DBName,
+		SSLMode:         c.Postgres.SSLMode,
+		MaxOpenConns:    c.Postgres.MaxOpenConns,
+		MaxIdleConns:    c.Postgres.MaxIdleConns,
+		ConnMaxLifetime: c.Postgres.ConnMaxLifetime,
+		ConnMaxIdleTime: c.Postgres.ConnMaxIdleTime,
+		ConnectTimeout:  c.Postgres.ConnectTimeout,
+	}
+}
+
+// SQLiteConfig is the configuration for db.NewSQLiteDB
+func (c *Config) SQLiteConfig() db.SQLiteConfig {
+	return db.SQLiteConfig{
+		Path:        c.SQLite.Path,
+		BusyTimeout: c.SQLite.BusyTimeout,
+	}
+}
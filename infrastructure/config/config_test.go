@@ -0,0 +1,227 @@
+package config_test
+
+import (
+	"HotelService/domain/model"
+	"HotelService/infrastructure/config"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// env is a lookupEnv over a fixed environment
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+// invalidFields returns the sorted fields of the validation error err
+func invalidFields(t *testing.T, err error) []string {
+	t.Helper()
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("error = %v, want a *model.ValidationError", err)
+	}
+
+	fields := make([]string, len(verr.Fields))
+	for i, field := range verr.Fields {
+		fields[i] = field.Field
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "config.yaml", `
+storage: memory
+server:
+  port: 8081
+  request_timeout: 10s
+auth:
+  token_secret: from-file
+`)
+
+	tests := []struct {
+		name       string
+		env        map[string]string
+		args       []string
+		wantPort   int
+		wantSecret string
+	}{
+		{
+			name:       "file",
+			env:        map[string]string{config.FileEnv: file},
+			wantPort:   8081,
+			wantSecret: "from-file",
+		},
+		{
+			name:       "env over file",
+			env:        map[string]string{config.FileEnv: file, "PORT": "8082"},
+			wantPort:   8082,
+			wantSecret: "from-file",
+		},
+		{
+			name:       "flag over env",
+			env:        map[string]string{config.FileEnv: file, "PORT": "8082", "AUTH_TOKEN_SECRET": "from-env"},
+			args:       []string{"-server.port", "8083"},
+			wantPort:   8083,
+			wantSecret: "from-env",
+		},
+		{
+			name:       "-config over CONFIG_FILE",
+			env:        map[string]string{config.FileEnv: "missing.yaml"},
+			args:       []string{"-config", file},
+			wantPort:   8081,
+			wantSecret: "from-file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.Load("server", tt.args, env(tt.env))
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			if cfg.Server.Port != tt.wantPort {
+				t.Errorf("server.port = %d, want %d", cfg.Server.Port, tt.wantPort)
+			}
+			if cfg.Auth.TokenSecret != tt.wantSecret {
+				t.Errorf("auth.token_secret = %q, want %q", cfg.Auth.TokenSecret, tt.wantSecret)
+			}
+			// set in the file only
+			if cfg.Server.RequestTimeout != 10*time.Second {
+				t.Errorf("server.request_timeout = %v, want 10s", cfg.Server.RequestTimeout)
+			}
+			// set nowhere
+			if want := config.Default().Server.IdleTimeout; cfg.Server.IdleTimeout != want {
+				t.Errorf("server.idle_timeout = %v, want the default %v", cfg.Server.IdleTimeout, want)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsUnknownFileKeys(t *testing.T) {
+	file := writeFile(t, "config.yaml", "server:\n  prot: 8081\n")
+
+	_, err := config.Load("server", []string{"-config", file}, env(nil))
+	if err == nil {
+		t.Fatal("Load of a file with a misspelt key succeeded, want an error")
+	}
+}
+
+func TestLoadSecretFiles(t *testing.T) {
+	secret := writeFile(t, "token_secret", "s3cret\n")
+	password := writeFile(t, "db_password", "pa55word\r\n")
+
+	cfg, err := config.Load("server", nil, env(map[string]string{
+		"AUTH_TOKEN_SECRET_FILE": secret,
+		"DB_HOST":                "localhost",
+		"DB_USER":                "hotel",
+		"DB_NAME":                "hotel",
+		"DB_PASSWORD_FILE":       password,
+	}))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Auth.TokenSecret != "s3cret" {
+		t.Errorf("auth.token_secret = %q, want s3cret", cfg.Auth.TokenSecret)
+	}
+	if cfg.Postgres.Password != "pa55word" {
+		t.Errorf("postgres.password = %q, want pa55word", cfg.Postgres.Password)
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []string
+	}{
+		{
+			name: "secret and its file",
+			env:  map[string]string{"STORAGE": "memory", "AUTH_TOKEN_SECRET": "s", "AUTH_TOKEN_SECRET_FILE": secret},
+			want: []string{"auth.token_secret"},
+		},
+		{
+			name: "missing file",
+			env:  map[string]string{"STORAGE": "memory", "AUTH_TOKEN_SECRET_FILE": filepath.Join(t.TempDir(), "missing")},
+			want: []string{"auth.token_secret", "auth.token_secret_file"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := config.Load("server", nil, env(tt.env))
+			if got := invalidFields(t, err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("invalid fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadReportsEveryInvalidField(t *testing.T) {
+	_, err := config.Load("server", []string{"-server.request_timeout", "soon"}, env(map[string]string{
+		"PORT":                  "http",
+		"READ_TIMEOUT":          "-1s",
+		"SHUTDOWN_TIMEOUT":      "0s",
+		"MAX_REQUEST_BODY_SIZE": "0",
+		"TLS_CERT_FILE":         "cert.pem",
+		"SEED_SAMPLE_DATA":      "maybe",
+		"AUTH_TOKEN_TTL":        "0s",
+		"DB_PORT":               "70000",
+		"DB_SSLMODE":            "always",
+		"DB_MAX_OPEN_CONNS":     "2",
+		"DB_MAX_IDLE_CONNS":     "5",
+	}))
+
+	want := []string{
+		"auth.token_secret",
+		"auth.token_ttl",
+		"postgres.dbname",
+		"postgres.host",
+		"postgres.max_idle_conns",
+		"postgres.password",
+		"postgres.port",
+		"postgres.sslmode",
+		"postgres.user",
+		"seed_sample_data",
+		"server.max_request_body_size",
+		"server.port",
+		"server.read_timeout",
+		"server.request_timeout",
+		"server.shutdown_timeout",
+		"server.tls_key_file",
+	}
+	if got := invalidFields(t, err); !reflect.DeepEqual(got, want) {
+		t.Errorf("invalid fields = %v, want %v", got, want)
+	}
+}
+
+func TestLoadStorageValidatesOnlyTheBackend(t *testing.T) {
+	cfg, err := config.LoadStorage(config.StorageSQLite, env(map[string]string{"SQLITE_PATH": "test.db", "PORT": "0"}))
+	if err != nil {
+		t.Fatalf("LoadStorage: %v", err)
+	}
+	if cfg.SQLite.Path != "test.db" {
+		t.Errorf("sqlite.path = %q, want test.db", cfg.SQLite.Path)
+	}
+
+	_, err = config.LoadStorage(config.StoragePostgres, env(map[string]string{"DB_HOST": "localhost"}))
+	want := []string{"postgres.dbname", "postgres.password", "postgres.user"}
+	if got := invalidFields(t, err); !reflect.DeepEqual(got, want) {
+		t.Errorf("invalid fields = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"HotelService/application/validation"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the configuration file when the -config flag is not given
+const FileEnv = "CONFIG_FILE"
+
+// setting binds one field of Config to its environment variable and flag. The
+// flag is named after the field's key in the file, e.g. -postgres.host.
+type setting struct {
+	key   string
+	env   string
+	usage string
+	field func(c *Config) interface{}
+}
+
+var settings = []setting{
+	{"storage", "STORAGE", `storage backend: "postgres", "sqlite" or "memory"`, func(c *Config) interface{} { return &c.Storage }},
+	{"deleted_hotel_retention", "DELETED_HOTEL_RETENTION", "how long deleted hotels can be restored", func(c *Config) interface{} { return &c.DeletedHotelRetention }},
+	{"seed_sample_data", "SEED_SAMPLE_DATA", "load the sample hotels after migrating", func(c *Config) interface{} { return &c.SeedSampleData }},
+
+	{"server.bind_address", "BIND_ADDRESS", "host to listen on, empty for every interface", func(c *Config) interface{} { return &c.Server.BindAddress }},
+	{"server.port", "PORT", "port to listen on", func(c *Config) interface{} { return &c.Server.Port }},
+	{"server.read_header_timeout", "READ_HEADER_TIMEOUT", "time allowed to read request headers", func(c *Config) interface{} { return &c.Server.ReadHeaderTimeout }},
+	{"server.read_timeout", "READ_TIMEOUT", "time allowed to read a whole request", func(c *Config) interface{} { return &c.Server.ReadTimeout }},
+	{"server.write_timeout", "WRITE_TIMEOUT", "time allowed to write a response", func(c *Config) interface{} { return &c.Server.WriteTimeout }},
+	{"server.idle_timeout", "IDLE_TIMEOUT", "how long idle keep-alive connections stay open", func(c *Config) interface{} { return &c.Server.IdleTimeout }},
+	{"server.shutdown_delay", "SHUTDOWN_DELAY", "time the server keeps serving with /readyz failing on shutdown", func(c *Config) interface{} { return &c.Server.ShutdownDelay }},
+	{"server.shutdown_timeout", "SHUTDOWN_TIMEOUT", "time in-flight requests get to finish on shutdown", func(c *Config) interface{} { return &c.Server.ShutdownTimeout }},
+	{"server.request_timeout", "REQUEST_TIMEOUT", "deadline of each request", func(c *Config) interface{} { return &c.Server.RequestTimeout }},
+	{"server.max_request_body_size", "MAX_REQUEST_BODY_SIZE", "largest request body accepted, in bytes", func(c *Config) interface{} { return &c.Server.MaxRequestBodySize }},
+	{"server.tls_cert_file", "TLS_CERT_FILE", "TLS certificate file; serves HTTPS with server.tls_key_file", func(c *Config) interface{} { return &c.Server.TLSCertFile }},
+	{"server.tls_key_file", "TLS_KEY_FILE", "TLS private key file", func(c *Config) interface{} { return &c.Server.TLSKeyFile }},
+
+	{"auth.token_secret", "AUTH_TOKEN_SECRET", "secret signing hotelier access tokens", func(c *Config) interface{} { return &c.Auth.TokenSecret }},
+	{"auth.token_secret_file", "AUTH_TOKEN_SECRET_FILE", "file holding auth.token_secret", func(c *Config) interface{} { return &c.Auth.TokenSecretFile }},
+	{"auth.token_ttl", "AUTH_TOKEN_TTL", "how long a hotelier login stays valid", func(c *Config) interface{} { return &c.Auth.TokenTTL }},
+
+	{"postgres.host", "DB_HOST", "Postgres host", func(c *Config) interface{} { return &c.Postgres.Host }},
+	{"postgres.port", "DB_PORT", "Postgres port", func(c *Config) interface{} { return &c.Postgres.Port }},
+	{"postgres.user", "DB_USER", "Postgres user", func(c *Config) interface{} { return &c.Postgres.User }},
+	{"postgres.password", "DB_PASSWORD", "Postgres password", func(c *Config) interface{} { return &c.Postgres.Password }},
+	{"postgres.password_file", "DB_PASSWORD_FILE", "file holding postgres.password", func(c *Config) interface{} { return &c.Postgres.PasswordFile }},
+	{"postgres.dbname", "DB_NAME", "Postgres database name", func(c *Config) interface{} { return &c.Postgres.DBName }},
+	{"postgres.sslmode", "DB_SSLMODE", "Postgres sslmode", func(c *Config) interface{} { return &c.Postgres.SSLMode }},
+	{"postgres.max_open_conns", "DB_MAX_OPEN_CONNS", "most open connections, 0 for no limit", func(c *Config) interface{} { return &c.Postgres.MaxOpenConns }},
+	{"postgres.max_idle_conns", "DB_MAX_IDLE_CONNS", "most idle connections kept in the pool", func(c *Config) interface{} { return &c.Postgres.MaxIdleConns }},
+	{"postgres.conn_max_lifetime", "DB_CONN_MAX_LIFETIME", "how long a connection is reused, 0 for ever", func(c *Config) interface{} { return &c.Postgres.ConnMaxLifetime }},
+	{"postgres.conn_max_idle_time", "DB_CONN_MAX_IDLE_TIME", "how long a connection stays idle, 0 for ever", func(c *Config) interface{} { return &c.Postgres.ConnMaxIdleTime }},
+	{"postgres.connect_timeout", "DB_CONNECT_TIMEOUT", "time allowed to connect, 0 to wait indefinitely", func(c *Config) interface{} { return &c.Postgres.ConnectTimeout }},
+
+	{"sqlite.path", "SQLITE_PATH", "SQLite database file", func(c *Config) interface{} { return &c.SQLite.Path }},
+	{"sqlite.busy_timeout", "SQLITE_BUSY_TIMEOUT", "how long SQLite waits for a lock", func(c *Config) interface{} { return &c.SQLite.BusyTimeout }},
+}
+
+// Load builds the server configuration from args, the flags after the program
+// name, and lookupEnv, usually os.LookupEnv. The file is named by -config or
+// CONFIG_FILE. Every invalid setting is reported in one *model.ValidationError
+// whose fields are the file keys; flag.ErrHelp is returned for -h.
+func Load(name string, args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	file := fs.String("config", "", "YAML or JSON configuration file (env "+FileEnv+")")
+	flags := make(map[string]*string, len(settings))
+	for _, s := range settings {
+		flags[s.key] = fs.String(s.key, "", s.usage+" (env "+s.env+")")
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	setFlags := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		if value, ok := flags[f.Name]; ok {
+			setFlags[f.Name] = *value
+		}
+	})
+
+	cfg, v, err := load(*file, lookupEnv, setFlags)
+	if err != nil {
+		return nil, err
+	}
+	cfg.validateServer(v)
+	cfg.validateStorage(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadStorage builds only the storage settings, from CONFIG_FILE and the
+// environment, for tools that work on the database directly. storage selects
+// the backend whose settings are validated.
+func LoadStorage(storage string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg, v, err := load("", lookupEnv, map[string]string{"storage": storage})
+	if err != nil {
+		return nil, err
+	}
+	cfg.validateStorage(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// load layers the file, the environment and flags over the defaults and reads
+// the secret files. Invalid values are collected in the returned validator;
+// only an unreadable configuration file fails right away.
+func load(file string, lookupEnv func(string) (string, bool), flags map[string]string) (*Config, *validation.Validator, error) {
+	cfg := Default()
+	v := validation.New()
+
+	if file == "" {
+		file, _ = lookupEnv(FileEnv)
+	}
+	if file != "" {
+		if err := cfg.readFile(file); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := lookupEnv(s.env); ok {
+			if err := s.set(&cfg, value); err != nil {
+				v.Add(s.key, "has an invalid value in %s: %v", s.env, err)
+			}
+		}
+	}
+	for _, s := range settings {
+		if value, ok := flags[s.key]; ok {
+			if err := s.set(&cfg, value); err != nil {
+				v.Add(s.key, "has an invalid value in -%s: %v", s.key, err)
+			}
+		}
+	}
+
+	readSecret(v, "auth.token_secret", &cfg.Auth.TokenSecret, cfg.Auth.TokenSecretFile)
+	readSecret(v, "postgres.password", &cfg.Postgres.Password, cfg.Postgres.PasswordFile)
+
+	return &cfg, v, nil
+}
+
+// readFile overlays the settings present in a YAML file; JSON is read the same
+// way since it is a subset of YAML. Unknown keys are rejected so that typos do
+// not go unnoticed.
+func (c *Config) readFile(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return fmt.Errorf("configuration file %s must be .yaml, .yml or .json", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// set parses value into the field of the setting
+func (s setting) set(c *Config, value string) error {
+	switch field := s.field(c).(type) {
+	case *string:
+		*field = value
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("expected true or false")
+		}
+		*field = b
+	case *int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("expected a whole number")
+		}
+		*field = n
+	case *int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return errors.New("expected a whole number")
+		}
+		*field = n
+	case *time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.New("expected a duration such as 30s")
+		}
+		*field = d
+	default:
+		panic(fmt.Sprintf("config: unsupported type %T for %s", field, s.key))
+	}
+	return nil
+}
+
+// readSecret replaces *secret with the contents of file, when one is given.
+// Trailing newlines, which editors and echo add, are not part of the secret.
+func readSecret(v *validation.Validator, key string, secret *string, file string) {
+	if file == "" {
+		return
+	}
+	if *secret != "" {
+		v.Add(key, "must not be set together with %s_file", key)
+		return
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		v.Add(key+"_file", "cannot be read: %v", err)
+		return
+	}
+	*secret = strings.TrimRight(string(data), "\r\n")
+}
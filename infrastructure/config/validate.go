@@ -0,0 +1,68 @@
+package config
+
+import (
+	"HotelService/application/validation"
+	"time"
+)
+
+var sslModes = map[string]bool{
+	"disable":     true,
+	"allow":       true,
+	"prefer":      true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+func (c *Config) validateServer(v *validation.Validator) {
+	s := c.Server
+	v.Check(s.Port > 0 && s.Port <= 65535, "server.port", "must be between 1 and 65535")
+	notNegative(v, "server.read_header_timeout", s.ReadHeaderTimeout)
+	notNegative(v, "server.read_timeout", s.ReadTimeout)
+	notNegative(v, "server.write_timeout", s.WriteTimeout)
+	notNegative(v, "server.idle_timeout", s.IdleTimeout)
+	notNegative(v, "server.shutdown_delay", s.ShutdownDelay)
+	v.Check(s.ShutdownTimeout > 0, "server.shutdown_timeout", "must be positive")
+	v.Check(s.RequestTimeout > 0, "server.request_timeout", "must be positive")
+	v.Check(s.MaxRequestBodySize > 0, "server.max_request_body_size", "must be positive")
+	if (s.TLSCertFile == "") != (s.TLSKeyFile == "") {
+		v.Add("server.tls_key_file", "must be set together with server.tls_cert_file")
+	}
+
+	v.Required("auth.token_secret", c.Auth.TokenSecret)
+	v.Check(c.Auth.TokenTTL > 0, "auth.token_ttl", "must be positive")
+
+	v.Check(c.DeletedHotelRetention > 0, "deleted_hotel_retention", "must be positive")
+}
+
+// validateStorage checks the settings of the selected backend only
+func (c *Config) validateStorage(v *validation.Validator) {
+	switch c.Storage {
+	case StoragePostgres:
+		p := c.Postgres
+		v.Required("postgres.host", p.Host)
+		v.Check(p.Port > 0 && p.Port <= 65535, "postgres.port", "must be between 1 and 65535")
+		v.Required("postgres.user", p.User)
+		v.Required("postgres.password", p.Password)
+		v.Required("postgres.dbname", p.DBName)
+		v.Check(sslModes[p.SSLMode], "postgres.sslmode", "must be one of disable, allow, prefer, require, verify-ca, verify-full")
+		v.Check(p.MaxOpenConns >= 0, "postgres.max_open_conns", "must not be negative")
+		v.Check(p.MaxIdleConns >= 0, "postgres.max_idle_conns", "must not be negative")
+		if p.MaxOpenConns > 0 && p.MaxIdleConns > p.MaxOpenConns {
+			v.Add("postgres.max_idle_conns", "must not exceed postgres.max_open_conns")
+		}
+		notNegative(v, "postgres.conn_max_lifetime", p.ConnMaxLifetime)
+		notNegative(v, "postgres.conn_max_idle_time", p.ConnMaxIdleTime)
+		notNegative(v, "postgres.connect_timeout", p.ConnectTimeout)
+	case StorageSQLite:
+		v.Required("sqlite.path", c.SQLite.Path)
+		notNegative(v, "sqlite.busy_timeout", c.SQLite.BusyTimeout)
+	case StorageMemory:
+	default:
+		v.Add("storage", "must be one of postgres, sqlite, memory")
+	}
+}
+
+func notNegative(v *validation.Validator, field string, d time.Duration) {
+	v.Check(d >= 0, field, "must not be negative")
+}
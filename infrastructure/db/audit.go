@@ -0,0 +1,145 @@
+package db
+
+import (
+	"HotelService/application/dto"
+	"HotelService/domain/model"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AuditPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditPostgresRepository {
+	return &AuditPostgresRepository{db: db}
+}
+
+func (r *AuditPostgresRepository) Save(ctx context.Context, entry *model.AuditEntry) error {
+	if entry == nil {
+		return fmt.Errorf("audit entry cannot be nil")
+	}
+
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+
+	query := `
+		INSERT INTO audit_log (hotel_id, actor_id, action, entity_type, entity_id, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	now := time.Now()
+	err = conn(ctx, r.db).QueryRowContext(ctx, query,
+		entry.HotelID,
+		sql.NullInt64{Int64: entry.ActorID, Valid: entry.ActorID != 0},
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		string(changes),
+		now,
+	).Scan(&entry.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to save audit entry: %w", err)
+	}
+
+	entry.CreatedAt = now
+	return nil
+}
+
+// auditSort lists entries newest first; ids grow with time
+var auditSort = keyset{columns: []string{"id"}, desc: true}
+
+func (r *AuditPostgresRepository) FindPage(ctx context.Context, filter dto.AuditFilter) ([]*model.AuditEntry, string, error) {
+	args := []interface{}{filter.HotelID}
+	conditions := []string{"hotel_id = $1"}
+
+	if filter.ActorID > 0 {
+		args = append(args, filter.ActorID)
+		conditions = append(conditions, fmt.Sprintf("actor_id = $%d", len(args)))
+	}
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conditions = append(conditions, fmt.Sprintf("action = $%d", len(args)))
+	}
+	if filter.EntityType != "" {
+		args = append(args, filter.EntityType)
+		conditions = append(conditions, fmt.Sprintf("entity_type = $%d", len(args)))
+	}
+	if filter.EntityID > 0 {
+		args = append(args, filter.EntityID)
+		conditions = append(conditions, fmt.Sprintf("entity_id = $%d", len(args)))
+	}
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if !filter.Until.IsZero() {
+		args = append(args, filter.Until)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+	if filter.Cursor != "" {
+		values, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		condition, err := auditSort.after(values, &args)
+		if err != nil {
+			return nil, "", err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	args = append(args, filter.Limit+1)
+	query := `
+		SELECT id, hotel_id, actor_id, action, entity_type, entity_id, changes, created_at
+		FROM audit_log
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + auditSort.orderBy() + fmt.Sprintf(`
+		LIMIT $%d`, len(args))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAuditPage(rows, filter.Limit)
+}
+
+// scanAuditPage reads up to limit entries and the cursor of the page after them
+func scanAuditPage(rows *sql.Rows, limit int) ([]*model.AuditEntry, string, error) {
+	var entries []*model.AuditEntry
+	for rows.Next() {
+		entry := &model.AuditEntry{}
+		var actorID sql.NullInt64
+		var changes string
+		if err := rows.Scan(&entry.ID, &entry.HotelID, &actorID, &entry.Action, &entry.EntityType, &entry.EntityID, &changes, &entry.CreatedAt); err != nil {
+			return nil, "", fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entry.ActorID = actorID.Int64
+		if err := json.Unmarshal([]byte(changes), &entry.Changes); err != nil {
+			return nil, "", fmt.Errorf("invalid stored audit changes: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("error iterating audit entries: %w", err)
+	}
+
+	var nextCursor string
+	if len(entries) > limit {
+		entries = entries[:limit]
+		nextCursor = encodeCursor([]string{strconv.FormatInt(entries[len(entries)-1].ID, 10)})
+	}
+
+	return entries, nextCursor, nil
+}
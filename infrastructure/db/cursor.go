@@ -0,0 +1,73 @@
+package db
+
+import (
+	"HotelService/domain/model"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// keyset describes a sort order usable for cursor pagination. The last column must
+// be unique (the primary key) so every row has a stable position.
+type keyset struct {
+	columns []string
+	desc    bool
+}
+
+func (k keyset) orderBy() string {
+	dir := "ASC"
+	if k.desc {
+		dir = "DESC"
+	}
+	parts := make([]string, len(k.columns))
+	for i, col := range k.columns {
+		parts[i] = col + " " + dir
+	}
+	return strings.Join(parts, ", ")
+}
+
+// after returns a condition selecting rows past the cursor position, appending its
+// parameters to args
+func (k keyset) after(values []string, args *[]interface{}) (string, error) {
+	if len(values) != len(k.columns) {
+		return "", model.NewValidationError("cursor does not match the requested sort")
+	}
+
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		*args = append(*args, v)
+		placeholders[i] = fmt.Sprintf("$%d", len(*args))
+	}
+
+	op := ">"
+	if k.desc {
+		op = "<"
+	}
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(k.columns, ", "), op, strings.Join(placeholders, ", ")), nil
+}
+
+func encodeCursor(values []string) string {
+	data, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) ([]string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, model.NewValidationError("invalid cursor")
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, model.NewValidationError("invalid cursor")
+	}
+
+	return values, nil
+}
+
+// likePattern matches value as a substring, escaping LIKE wildcards in it
+func likePattern(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(value) + "%"
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"HotelService/domain/model"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+	pgExclusionViolation  = "23P01"
+)
+
+func isConstraintViolation(err error, code, constraint string) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return string(pqErr.Code) == code && pqErr.Constraint == constraint
+}
+
+// staleOrMissing explains why a versioned update matched no row: the entity is
+// gone, or someone changed it after the caller read the given version
+func staleOrMissing(ctx context.Context, db *sql.DB, existsQuery, entity string, id, version int64) error {
+	found, err := exists(ctx, db, existsQuery, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return model.NewNotFoundError("%s with ID %d not found", entity, id)
+	}
+	return model.NewPreconditionFailedError("%s %d has changed since version %d", entity, id, version)
+}
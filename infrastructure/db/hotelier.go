@@ -0,0 +1,95 @@
+package db
+
+import (
+	"HotelService/domain/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type HotelierPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewHotelierRepository(db *sql.DB) *HotelierPostgresRepository {
+	return &HotelierPostgresRepository{db: db}
+}
+
+func (r *HotelierPostgresRepository) Save(ctx context.Context, hotelier *model.Hotelier) error {
+	if hotelier == nil {
+		return fmt.Errorf("hotelier cannot be nil")
+	}
+
+	query := `
+		INSERT INTO hoteliers (email, name, password_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	now := time.Now()
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		hotelier.Email,
+		hotelier.Name,
+		hotelier.PasswordHash,
+		now,
+		now,
+	).Scan(&hotelier.ID)
+
+	if err != nil {
+		if isConstraintViolation(err, pgUniqueViolation, "unique_hotelier_email") {
+			return model.NewConflictError("an account with email %s already exists", hotelier.Email)
+		}
+		return fmt.Errorf("failed to save hotelier: %w", err)
+	}
+
+	hotelier.CreatedAt = now
+	hotelier.UpdatedAt = now
+	return nil
+}
+
+func (r *HotelierPostgresRepository) FindByID(ctx context.Context, id int64) (*model.Hotelier, error) {
+	query := `
+		SELECT id, email, name, password_hash, created_at, updated_at
+		FROM hoteliers
+		WHERE id = $1`
+
+	hotelier, err := scanHotelier(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("hotelier with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to find hotelier: %w", err)
+	}
+
+	return hotelier, nil
+}
+
+func (r *HotelierPostgresRepository) FindByEmail(ctx context.Context, email string) (*model.Hotelier, error) {
+	query := `
+		SELECT id, email, name, password_hash, created_at, updated_at
+		FROM hoteliers
+		WHERE email = $1`
+
+	hotelier, err := scanHotelier(conn(ctx, r.db).QueryRowContext(ctx, query, email))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("hotelier with email %s not found", email)
+		}
+		return nil, fmt.Errorf("failed to find hotelier: %w", err)
+	}
+
+	return hotelier, nil
+}
+
+func scanHotelier(row rowScanner) (*model.Hotelier, error) {
+	hotelier := &model.Hotelier{}
+	err := row.Scan(
+		&hotelier.ID,
+		&hotelier.Email,
+		&hotelier.Name,
+		&hotelier.PasswordHash,
+		&hotelier.CreatedAt,
+		&hotelier.UpdatedAt,
+	)
+	return hotelier, err
+}
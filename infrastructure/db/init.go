@@ -1,11 +1,10 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
-	"os"
-	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -18,78 +17,62 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// Pool settings, see sql.DB. MaxOpenConns of 0 leaves the pool unbounded.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// ConnectTimeout bounds each new connection and the initial ping; 0 waits
+	// indefinitely
+	ConnectTimeout time.Duration
 }
 
 func NewPostgresDB(cfg Config) (*sql.DB, error) {
 
 	dsn := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host,
+		dsnValue(cfg.Host),
 		cfg.Port,
-		cfg.User,
-		cfg.Password,
-		cfg.DBName,
-		cfg.SSLMode,
+		dsnValue(cfg.User),
+		dsnValue(cfg.Password),
+		dsnValue(cfg.DBName),
+		dsnValue(cfg.SSLMode),
 	)
+	if cfg.ConnectTimeout > 0 {
+		// connect_timeout is in whole seconds
+		dsn += fmt.Sprintf(" connect_timeout=%d", int((cfg.ConnectTimeout+time.Second-1)/time.Second))
+	}
 
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
-	db.SetConnMaxIdleTime(10 * time.Minute)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	if err := db.Ping(); err != nil {
+	ctx := context.Background()
+	if cfg.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.ConnectTimeout)
+		defer cancel()
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	return db, nil
 }
 
-func DefaultConfig() Config {
-	host := os.Getenv("DB_HOST")
-	if host == "" {
-		log.Fatal("DB_HOST environment variable is required")
-	}
-
-	portStr := os.Getenv("DB_PORT")
-	if portStr == "" {
-		log.Fatal("DB_PORT environment variable is required")
-	}
-	port, err := strconv.Atoi(portStr)
-	if err != nil {
-		log.Fatal("Invalid DB_PORT:", err)
-	}
-
-	user := os.Getenv("DB_USER")
-	if user == "" {
-		log.Fatal("DB_USER environment variable is required")
-	}
-
-	password := os.Getenv("DB_PASSWORD")
-	if password == "" {
-		log.Fatal("DB_PASSWORD environment variable is required")
-	}
-
-	dbName := os.Getenv("DB_NAME")
-	if dbName == "" {
-		log.Fatal("DB_NAME environment variable is required")
-	}
-
-	sslMode := os.Getenv("DB_SSLMODE")
-	if sslMode == "" {
-		log.Fatal("DB_SSLMODE environment variable is required")
-	}
-
-	return Config{
-		Host:     host,
-		Port:     port,
-		User:     user,
-		Password: password,
-		DBName:   dbName,
-		SSLMode:  sslMode,
-	}
+// dsnValue quotes a connection string value so that it may contain spaces,
+// quotes and backslashes, e.g. a password read from a file
+func dsnValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
 }
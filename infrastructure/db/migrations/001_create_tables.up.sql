@@ -31,15 +31,3 @@ CREATE INDEX IF NOT EXISTS idx_rooms_available ON rooms(available);
 CREATE INDEX IF NOT EXISTS idx_rooms_type ON rooms(type);
 CREATE INDEX IF NOT EXISTS idx_rooms_price ON rooms(price);
 CREATE INDEX IF NOT EXISTS idx_hotels_created_at ON hotels(created_at);
-
--- Insert sample data (optional, remove if not needed)
-INSERT INTO hotels (name, address) VALUES 
-    ('Grand Hotel', 'Sudino, Glavnaya ul, 12'),
-    ('Ocean View Resort', '456 Beach Blvd, Miami, FL');
-
-INSERT INTO rooms (hotel_id, number, type, price, available) VALUES
-    (1, '101', 'Single', 100.00, true),
-    (1, '102', 'Double', 150.00, true),
-    (1, '103', 'Suite', 250.00, false),
-    (2, '201', 'Single', 120.00, true),
-    (2, '202', 'Double', 180.00, true);
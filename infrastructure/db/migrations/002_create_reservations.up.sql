@@ -0,0 +1,24 @@
+-- Create reservations table
+CREATE TABLE IF NOT EXISTS reservations (
+    id BIGSERIAL PRIMARY KEY,
+    room_id BIGINT NOT NULL,
+    guest_name VARCHAR(255) NOT NULL,
+    guest_email VARCHAR(255) NOT NULL,
+    check_in DATE NOT NULL,
+    check_out DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'confirmed',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_room
+        FOREIGN KEY (room_id)
+        REFERENCES rooms(id)
+        ON DELETE CASCADE,
+    CONSTRAINT check_stay_dates
+        CHECK (check_out > check_in),
+    CONSTRAINT check_reservation_status
+        CHECK (status IN ('confirmed', 'cancelled'))
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_reservations_room_id ON reservations(room_id);
+CREATE INDEX IF NOT EXISTS idx_reservations_stay ON reservations(room_id, check_in, check_out);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_rooms_capacity;
+ALTER TABLE rooms DROP CONSTRAINT IF EXISTS check_room_capacity;
+ALTER TABLE rooms DROP COLUMN IF EXISTS capacity;
@@ -0,0 +1,7 @@
+-- Add guest capacity to rooms
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS capacity INT NOT NULL DEFAULT 2;
+
+ALTER TABLE rooms DROP CONSTRAINT IF EXISTS check_room_capacity;
+ALTER TABLE rooms ADD CONSTRAINT check_room_capacity CHECK (capacity > 0);
+
+CREATE INDEX IF NOT EXISTS idx_rooms_capacity ON rooms(capacity);
@@ -0,0 +1,2 @@
+-- btree_gist is left installed; other schemas in the database may rely on it
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS no_overlapping_reservations;
@@ -0,0 +1,18 @@
+-- Prevent overlapping confirmed reservations for the same room at the database level
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'no_overlapping_reservations'
+    ) THEN
+        ALTER TABLE reservations
+            ADD CONSTRAINT no_overlapping_reservations
+            EXCLUDE USING gist (
+                room_id WITH =,
+                daterange(check_in, check_out, '[)') WITH &&
+            )
+            WHERE (status = 'confirmed');
+    END IF;
+END
+$$;
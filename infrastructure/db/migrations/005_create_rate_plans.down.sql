@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS rate_plan_day_modifiers;
+DROP TABLE IF EXISTS seasonal_rates;
+DROP TABLE IF EXISTS rate_plans;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_hotels_owner_id;
+ALTER TABLE hotels DROP CONSTRAINT IF EXISTS fk_hotel_owner;
+ALTER TABLE hotels DROP COLUMN IF EXISTS owner_id;
+DROP TABLE IF EXISTS hoteliers;
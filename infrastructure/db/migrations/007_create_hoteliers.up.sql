@@ -0,0 +1,30 @@
+-- Create hoteliers table
+CREATE TABLE IF NOT EXISTS hoteliers (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_hotelier_email
+        UNIQUE (email)
+);
+
+-- Hotels created before accounts existed have no owner and can only be read
+ALTER TABLE hotels ADD COLUMN IF NOT EXISTS owner_id BIGINT;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'fk_hotel_owner'
+    ) THEN
+        ALTER TABLE hotels
+            ADD CONSTRAINT fk_hotel_owner
+            FOREIGN KEY (owner_id)
+            REFERENCES hoteliers(id)
+            ON DELETE SET NULL;
+    END IF;
+END
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_hotels_owner_id ON hotels(owner_id);
@@ -0,0 +1,4 @@
+DELETE FROM role_permissions WHERE permission = 'hotel:delete';
+DROP INDEX IF EXISTS idx_hotels_deleted_at;
+ALTER TABLE rooms DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE hotels DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,10 @@
+-- Soft delete: a deleted hotel and its rooms stay hidden until restored, or
+-- until they are purged once the retention period has passed
+ALTER TABLE hotels ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_hotels_deleted_at ON hotels(deleted_at) WHERE deleted_at IS NOT NULL;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('owner', 'hotel:delete')
+ON CONFLICT (role, permission) DO NOTHING;
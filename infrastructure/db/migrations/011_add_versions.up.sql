@@ -0,0 +1,4 @@
+-- Versions for optimistic concurrency: every change of a hotel or room bumps
+-- its version, and a writer names the version it last read
+ALTER TABLE hotels ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
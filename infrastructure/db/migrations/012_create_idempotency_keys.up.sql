@@ -0,0 +1,18 @@
+-- Remember the response to every create a hotelier sent with an Idempotency-Key
+-- so that retries get it replayed; status_code is 0 while the first request runs
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    hotelier_id BIGINT NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (hotelier_id, idempotency_key),
+    CONSTRAINT fk_idempotency_hotelier
+        FOREIGN KEY (hotelier_id)
+        REFERENCES hoteliers(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
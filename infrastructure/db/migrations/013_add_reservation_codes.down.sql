@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reservations_code;
+ALTER TABLE reservations DROP COLUMN IF EXISTS code;
@@ -0,0 +1,6 @@
+-- Guests reach their booking with the random code they were given when booking
+-- it. Reservations made before codes existed have none; their guests use the
+-- email they booked with instead.
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS code VARCHAR(32);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reservations_code ON reservations(code);
@@ -0,0 +1,6 @@
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS fk_room;
+ALTER TABLE reservations
+    ADD CONSTRAINT fk_room
+        FOREIGN KEY (room_id)
+        REFERENCES rooms(id)
+        ON DELETE CASCADE;
@@ -0,0 +1,8 @@
+-- Deleting a room must not silently take its guests' bookings with it: the
+-- room can only go once its reservations are removed deliberately
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS fk_room;
+ALTER TABLE reservations
+    ADD CONSTRAINT fk_room
+        FOREIGN KEY (room_id)
+        REFERENCES rooms(id)
+        ON DELETE RESTRICT;
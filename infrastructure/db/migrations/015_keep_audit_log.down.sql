@@ -0,0 +1,9 @@
+DELETE FROM audit_log
+WHERE actor_id IS NULL OR hotel_id NOT IN (SELECT id FROM hotels);
+
+ALTER TABLE audit_log ALTER COLUMN actor_id SET NOT NULL;
+ALTER TABLE audit_log
+    ADD CONSTRAINT fk_audit_hotel
+        FOREIGN KEY (hotel_id)
+        REFERENCES hotels(id)
+        ON DELETE CASCADE;
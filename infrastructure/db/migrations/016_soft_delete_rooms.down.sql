@@ -0,0 +1,24 @@
+-- Rooms deleted on their own, rather than with their hotel, were removed for
+-- good before; remove them again with their reservations
+DELETE FROM reservations
+WHERE room_id IN (
+    SELECT r.id
+    FROM rooms r
+    JOIN hotels h ON h.id = r.hotel_id
+    WHERE r.deleted_at IS NOT NULL
+      AND (h.deleted_at IS NULL OR r.deleted_at < h.deleted_at)
+);
+
+DELETE FROM rooms
+WHERE id IN (
+    SELECT r.id
+    FROM rooms r
+    JOIN hotels h ON h.id = r.hotel_id
+    WHERE r.deleted_at IS NOT NULL
+      AND (h.deleted_at IS NULL OR r.deleted_at < h.deleted_at)
+);
+
+DROP INDEX IF EXISTS unique_room_number_per_hotel;
+ALTER TABLE rooms
+    ADD CONSTRAINT unique_room_number_per_hotel
+        UNIQUE (hotel_id, number);
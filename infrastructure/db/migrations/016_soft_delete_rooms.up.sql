@@ -0,0 +1,6 @@
+-- A deleted room is soft-deleted like a hotel, so that its reservations stay on
+-- record. Its number is free again for a new room.
+ALTER TABLE rooms DROP CONSTRAINT IF EXISTS unique_room_number_per_hotel;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_room_number_per_hotel
+    ON rooms(hotel_id, number)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS rooms;
+DROP TABLE IF EXISTS hotels;
@@ -0,0 +1,33 @@
+-- Create hotels table
+CREATE TABLE IF NOT EXISTS hotels (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    address TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create rooms table
+CREATE TABLE IF NOT EXISTS rooms (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    hotel_id INTEGER NOT NULL,
+    number VARCHAR(50) NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    price DECIMAL(10,2) NOT NULL,
+    available BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_hotel
+        FOREIGN KEY (hotel_id)
+        REFERENCES hotels(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_room_number_per_hotel
+        UNIQUE (hotel_id, number)
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_rooms_hotel_id ON rooms(hotel_id);
+CREATE INDEX IF NOT EXISTS idx_rooms_available ON rooms(available);
+CREATE INDEX IF NOT EXISTS idx_rooms_type ON rooms(type);
+CREATE INDEX IF NOT EXISTS idx_rooms_price ON rooms(price);
+CREATE INDEX IF NOT EXISTS idx_hotels_created_at ON hotels(created_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_rooms_capacity;
+ALTER TABLE rooms DROP COLUMN capacity;
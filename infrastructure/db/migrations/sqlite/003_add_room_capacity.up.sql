@@ -0,0 +1,5 @@
+-- Add guest capacity to rooms; SQLite only accepts the check as part of the new column
+ALTER TABLE rooms ADD COLUMN capacity INT NOT NULL DEFAULT 2
+    CONSTRAINT check_room_capacity CHECK (capacity > 0);
+
+CREATE INDEX IF NOT EXISTS idx_rooms_capacity ON rooms(capacity);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS no_overlapping_reservations_update;
+DROP TRIGGER IF EXISTS no_overlapping_reservations_insert;
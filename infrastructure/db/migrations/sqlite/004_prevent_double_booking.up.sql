@@ -0,0 +1,32 @@
+-- Prevent overlapping confirmed reservations for the same room at the database level.
+-- SQLite has no exclusion constraints, so triggers reject the overlap instead.
+CREATE TRIGGER IF NOT EXISTS no_overlapping_reservations_insert
+BEFORE INSERT ON reservations
+WHEN NEW.status = 'confirmed'
+BEGIN
+    SELECT RAISE(ABORT, 'no_overlapping_reservations')
+    WHERE EXISTS (
+        SELECT 1
+        FROM reservations
+        WHERE room_id = NEW.room_id
+          AND status = 'confirmed'
+          AND check_in < NEW.check_out
+          AND check_out > NEW.check_in
+    );
+END;
+
+CREATE TRIGGER IF NOT EXISTS no_overlapping_reservations_update
+BEFORE UPDATE OF room_id, check_in, check_out, status ON reservations
+WHEN NEW.status = 'confirmed'
+BEGIN
+    SELECT RAISE(ABORT, 'no_overlapping_reservations')
+    WHERE EXISTS (
+        SELECT 1
+        FROM reservations
+        WHERE id <> NEW.id
+          AND room_id = NEW.room_id
+          AND status = 'confirmed'
+          AND check_in < NEW.check_out
+          AND check_out > NEW.check_in
+    );
+END;
@@ -0,0 +1,60 @@
+-- Create rate plans table
+CREATE TABLE IF NOT EXISTS rate_plans (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    hotel_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_rate_plan_hotel
+        FOREIGN KEY (hotel_id)
+        REFERENCES hotels(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_rate_plan_name_per_hotel
+        UNIQUE (hotel_id, name)
+);
+
+-- Create seasonal rates table; a rate targets one room, one room type or the whole hotel
+CREATE TABLE IF NOT EXISTS seasonal_rates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    rate_plan_id INTEGER NOT NULL,
+    room_id INTEGER,
+    room_type VARCHAR(100),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    price DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_seasonal_rate_plan
+        FOREIGN KEY (rate_plan_id)
+        REFERENCES rate_plans(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_seasonal_rate_room
+        FOREIGN KEY (room_id)
+        REFERENCES rooms(id)
+        ON DELETE CASCADE,
+    CONSTRAINT check_season_dates
+        CHECK (end_date > start_date),
+    CONSTRAINT check_season_price
+        CHECK (price > 0),
+    CONSTRAINT check_season_target
+        CHECK (room_id IS NULL OR room_type IS NULL)
+);
+
+-- Create day-of-week modifiers table (weekday 0 = Sunday)
+CREATE TABLE IF NOT EXISTS rate_plan_day_modifiers (
+    rate_plan_id INTEGER NOT NULL,
+    weekday SMALLINT NOT NULL,
+    multiplier DECIMAL(6,4) NOT NULL,
+    PRIMARY KEY (rate_plan_id, weekday),
+    CONSTRAINT fk_day_modifier_plan
+        FOREIGN KEY (rate_plan_id)
+        REFERENCES rate_plans(id)
+        ON DELETE CASCADE,
+    CONSTRAINT check_weekday
+        CHECK (weekday BETWEEN 0 AND 6),
+    CONSTRAINT check_multiplier
+        CHECK (multiplier > 0)
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_rate_plans_hotel_id ON rate_plans(hotel_id);
+CREATE INDEX IF NOT EXISTS idx_seasonal_rates_plan ON seasonal_rates(rate_plan_id, start_date, end_date);
@@ -0,0 +1,2 @@
+-- Add the ISO 4217 currency all prices of a hotel are quoted in
+ALTER TABLE hotels ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_hotels_owner_id;
+DROP TRIGGER IF EXISTS fk_hotel_owner_delete;
+DROP TRIGGER IF EXISTS fk_hotel_owner_update;
+DROP TRIGGER IF EXISTS fk_hotel_owner_insert;
+ALTER TABLE hotels DROP COLUMN owner_id;
+DROP TABLE IF EXISTS hoteliers;
@@ -0,0 +1,40 @@
+-- Create hoteliers table
+CREATE TABLE IF NOT EXISTS hoteliers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_hotelier_email
+        UNIQUE (email)
+);
+
+-- Hotels created before accounts existed have no owner and can only be read
+ALTER TABLE hotels ADD COLUMN owner_id INTEGER;
+
+-- SQLite cannot drop a column that is part of a foreign key, so fk_hotel_owner
+-- is enforced by triggers to keep this migration reversible
+CREATE TRIGGER IF NOT EXISTS fk_hotel_owner_insert
+BEFORE INSERT ON hotels
+WHEN NEW.owner_id IS NOT NULL
+BEGIN
+    SELECT RAISE(ABORT, 'fk_hotel_owner')
+    WHERE NOT EXISTS (SELECT 1 FROM hoteliers WHERE id = NEW.owner_id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS fk_hotel_owner_update
+BEFORE UPDATE OF owner_id ON hotels
+WHEN NEW.owner_id IS NOT NULL
+BEGIN
+    SELECT RAISE(ABORT, 'fk_hotel_owner')
+    WHERE NOT EXISTS (SELECT 1 FROM hoteliers WHERE id = NEW.owner_id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS fk_hotel_owner_delete
+AFTER DELETE ON hoteliers
+BEGIN
+    UPDATE hotels SET owner_id = NULL WHERE owner_id = OLD.id;
+END;
+
+CREATE INDEX IF NOT EXISTS idx_hotels_owner_id ON hotels(owner_id);
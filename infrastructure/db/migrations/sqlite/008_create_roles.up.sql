@@ -0,0 +1,67 @@
+-- Create roles and the permissions each role holds
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(50) NOT NULL,
+    permission VARCHAR(50) NOT NULL,
+    PRIMARY KEY (role, permission),
+    CONSTRAINT fk_role_permission_role
+        FOREIGN KEY (role)
+        REFERENCES roles(name)
+        ON DELETE CASCADE
+);
+
+-- Create hotel staff table; the owner is recorded on hotels.owner_id instead
+CREATE TABLE IF NOT EXISTS hotel_staff (
+    hotel_id INTEGER NOT NULL,
+    hotelier_id INTEGER NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (hotel_id, hotelier_id),
+    CONSTRAINT fk_staff_hotel
+        FOREIGN KEY (hotel_id)
+        REFERENCES hotels(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_staff_hotelier
+        FOREIGN KEY (hotelier_id)
+        REFERENCES hoteliers(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_staff_role
+        FOREIGN KEY (role)
+        REFERENCES roles(name),
+    CONSTRAINT check_staff_role
+        CHECK (role <> 'owner')
+);
+
+CREATE INDEX IF NOT EXISTS idx_hotel_staff_hotelier_id ON hotel_staff(hotelier_id);
+
+INSERT INTO roles (name, description) VALUES
+    ('owner', 'Owns the hotel and may do everything'),
+    ('front_desk', 'Handles guests: room availability and reservations'),
+    ('housekeeping', 'Takes rooms out of and back into service'),
+    ('revenue_manager', 'Sets room prices and rate plans')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('owner', 'hotel:view'),
+    ('owner', 'hotel:update'),
+    ('owner', 'rooms:manage'),
+    ('owner', 'rooms:update'),
+    ('owner', 'rooms:availability'),
+    ('owner', 'reservations:view'),
+    ('owner', 'rate_plans:view'),
+    ('owner', 'rate_plans:manage'),
+    ('owner', 'staff:manage'),
+    ('front_desk', 'hotel:view'),
+    ('front_desk', 'rooms:availability'),
+    ('front_desk', 'reservations:view'),
+    ('housekeeping', 'hotel:view'),
+    ('housekeeping', 'rooms:availability'),
+    ('revenue_manager', 'hotel:view'),
+    ('revenue_manager', 'rooms:update'),
+    ('revenue_manager', 'rate_plans:view'),
+    ('revenue_manager', 'rate_plans:manage')
+ON CONFLICT (role, permission) DO NOTHING;
@@ -0,0 +1,2 @@
+DELETE FROM role_permissions WHERE permission = 'audit:view';
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,25 @@
+-- Create the audit log of changes hoteliers make at their hotels; changes maps
+-- each changed field to its value before and after
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    hotel_id INTEGER NOT NULL,
+    actor_id INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    changes TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_audit_hotel
+        FOREIGN KEY (hotel_id)
+        REFERENCES hotels(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_audit_actor
+        FOREIGN KEY (actor_id)
+        REFERENCES hoteliers(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_hotel_id ON audit_log(hotel_id, id);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('owner', 'audit:view')
+ON CONFLICT (role, permission) DO NOTHING;
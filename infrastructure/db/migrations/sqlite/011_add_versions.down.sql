@@ -0,0 +1,2 @@
+ALTER TABLE rooms DROP COLUMN version;
+ALTER TABLE hotels DROP COLUMN version;
@@ -0,0 +1,64 @@
+-- Rebuild the table with the cascading foreign key of 002
+CREATE TABLE reservations_rebuilt (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    room_id INTEGER NOT NULL,
+    guest_name VARCHAR(255) NOT NULL,
+    guest_email VARCHAR(255) NOT NULL,
+    check_in DATE NOT NULL,
+    check_out DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'confirmed',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    code VARCHAR(32),
+    CONSTRAINT fk_room
+        FOREIGN KEY (room_id)
+        REFERENCES rooms(id)
+        ON DELETE CASCADE,
+    CONSTRAINT check_stay_dates
+        CHECK (check_out > check_in),
+    CONSTRAINT check_reservation_status
+        CHECK (status IN ('confirmed', 'cancelled'))
+);
+
+INSERT INTO reservations_rebuilt (id, room_id, guest_name, guest_email, check_in, check_out, status, created_at, updated_at, code)
+SELECT id, room_id, guest_name, guest_email, check_in, check_out, status, created_at, updated_at, code
+FROM reservations;
+
+DROP TABLE reservations;
+ALTER TABLE reservations_rebuilt RENAME TO reservations;
+
+-- Dropping the table dropped its indexes and triggers
+CREATE INDEX IF NOT EXISTS idx_reservations_room_id ON reservations(room_id);
+CREATE INDEX IF NOT EXISTS idx_reservations_stay ON reservations(room_id, check_in, check_out);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reservations_code ON reservations(code);
+
+CREATE TRIGGER IF NOT EXISTS no_overlapping_reservations_insert
+BEFORE INSERT ON reservations
+WHEN NEW.status = 'confirmed'
+BEGIN
+    SELECT RAISE(ABORT, 'no_overlapping_reservations')
+    WHERE EXISTS (
+        SELECT 1
+        FROM reservations
+        WHERE room_id = NEW.room_id
+          AND status = 'confirmed'
+          AND check_in < NEW.check_out
+          AND check_out > NEW.check_in
+    );
+END;
+
+CREATE TRIGGER IF NOT EXISTS no_overlapping_reservations_update
+BEFORE UPDATE OF room_id, check_in, check_out, status ON reservations
+WHEN NEW.status = 'confirmed'
+BEGIN
+    SELECT RAISE(ABORT, 'no_overlapping_reservations')
+    WHERE EXISTS (
+        SELECT 1
+        FROM reservations
+        WHERE id <> NEW.id
+          AND room_id = NEW.room_id
+          AND status = 'confirmed'
+          AND check_in < NEW.check_out
+          AND check_out > NEW.check_in
+    );
+END;
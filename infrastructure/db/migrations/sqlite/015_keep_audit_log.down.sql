@@ -0,0 +1,28 @@
+-- Rebuild the table with the constraints of 010
+CREATE TABLE audit_log_rebuilt (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    hotel_id INTEGER NOT NULL,
+    actor_id INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    changes TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_audit_hotel
+        FOREIGN KEY (hotel_id)
+        REFERENCES hotels(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_audit_actor
+        FOREIGN KEY (actor_id)
+        REFERENCES hoteliers(id)
+);
+
+INSERT INTO audit_log_rebuilt (id, hotel_id, actor_id, action, entity_type, entity_id, changes, created_at)
+SELECT id, hotel_id, actor_id, action, entity_type, entity_id, changes, created_at
+FROM audit_log
+WHERE actor_id IS NOT NULL AND hotel_id IN (SELECT id FROM hotels);
+
+DROP TABLE audit_log;
+ALTER TABLE audit_log_rebuilt RENAME TO audit_log;
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_hotel_id ON audit_log(hotel_id, id);
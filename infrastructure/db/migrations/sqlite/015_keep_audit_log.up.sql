@@ -0,0 +1,27 @@
+-- A hotel's audit log outlives the hotel: purging it must not erase the record
+-- of what was done there. The purge job writes the last entry itself and acts
+-- for no hotelier, so it leaves actor_id empty. SQLite cannot drop a foreign
+-- key, so the table is rebuilt.
+CREATE TABLE audit_log_rebuilt (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    hotel_id INTEGER NOT NULL,
+    actor_id INTEGER,
+    action VARCHAR(20) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    changes TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_audit_actor
+        FOREIGN KEY (actor_id)
+        REFERENCES hoteliers(id)
+);
+
+INSERT INTO audit_log_rebuilt (id, hotel_id, actor_id, action, entity_type, entity_id, changes, created_at)
+SELECT id, hotel_id, actor_id, action, entity_type, entity_id, changes, created_at
+FROM audit_log;
+
+DROP TABLE audit_log;
+ALTER TABLE audit_log_rebuilt RENAME TO audit_log;
+
+-- Dropping the table dropped its index
+CREATE INDEX IF NOT EXISTS idx_audit_log_hotel_id ON audit_log(hotel_id, id);
@@ -0,0 +1,48 @@
+-- Rooms deleted on their own, rather than with their hotel, were removed for
+-- good before; remove them again with their reservations and seasonal rates
+CREATE TEMPORARY TABLE deleted_rooms AS
+SELECT r.id
+FROM rooms r
+JOIN hotels h ON h.id = r.hotel_id
+WHERE r.deleted_at IS NOT NULL
+  AND (h.deleted_at IS NULL OR r.deleted_at < h.deleted_at);
+
+DELETE FROM reservations WHERE room_id IN (SELECT id FROM deleted_rooms);
+DELETE FROM seasonal_rates WHERE room_id IN (SELECT id FROM deleted_rooms);
+
+-- Rebuild the table with the unique constraint of 001
+CREATE TABLE rooms_rebuilt (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    hotel_id INTEGER NOT NULL,
+    number VARCHAR(50) NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    price DECIMAL(10,2) NOT NULL,
+    available BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    capacity INT NOT NULL DEFAULT 2
+        CONSTRAINT check_room_capacity CHECK (capacity > 0),
+    deleted_at TIMESTAMP,
+    version INTEGER NOT NULL DEFAULT 1,
+    CONSTRAINT fk_hotel
+        FOREIGN KEY (hotel_id)
+        REFERENCES hotels(id)
+        ON DELETE CASCADE,
+    CONSTRAINT unique_room_number_per_hotel
+        UNIQUE (hotel_id, number)
+);
+
+INSERT INTO rooms_rebuilt (id, hotel_id, number, type, price, available, created_at, updated_at, capacity, deleted_at, version)
+SELECT id, hotel_id, number, type, price, available, created_at, updated_at, capacity, deleted_at, version
+FROM rooms
+WHERE id NOT IN (SELECT id FROM deleted_rooms);
+
+DROP TABLE deleted_rooms;
+DROP TABLE rooms;
+ALTER TABLE rooms_rebuilt RENAME TO rooms;
+
+CREATE INDEX IF NOT EXISTS idx_rooms_hotel_id ON rooms(hotel_id);
+CREATE INDEX IF NOT EXISTS idx_rooms_available ON rooms(available);
+CREATE INDEX IF NOT EXISTS idx_rooms_type ON rooms(type);
+CREATE INDEX IF NOT EXISTS idx_rooms_price ON rooms(price);
+CREATE INDEX IF NOT EXISTS idx_rooms_capacity ON rooms(capacity);
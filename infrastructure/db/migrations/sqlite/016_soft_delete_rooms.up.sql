@@ -0,0 +1,37 @@
+-- A deleted room is soft-deleted like a hotel, so that its reservations stay on
+-- record. Its number is free again for a new room. SQLite cannot drop a unique
+-- constraint, so the table is rebuilt; the migrator turns foreign keys off
+-- while it runs and checks them before committing.
+CREATE TABLE rooms_rebuilt (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    hotel_id INTEGER NOT NULL,
+    number VARCHAR(50) NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    price DECIMAL(10,2) NOT NULL,
+    available BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    capacity INT NOT NULL DEFAULT 2
+        CONSTRAINT check_room_capacity CHECK (capacity > 0),
+    deleted_at TIMESTAMP,
+    version INTEGER NOT NULL DEFAULT 1,
+    CONSTRAINT fk_hotel
+        FOREIGN KEY (hotel_id)
+        REFERENCES hotels(id)
+        ON DELETE CASCADE
+);
+
+INSERT INTO rooms_rebuilt (id, hotel_id, number, type, price, available, created_at, updated_at, capacity, deleted_at, version)
+SELECT id, hotel_id, number, type, price, available, created_at, updated_at, capacity, deleted_at, version
+FROM rooms;
+
+DROP TABLE rooms;
+ALTER TABLE rooms_rebuilt RENAME TO rooms;
+
+-- Dropping the table dropped its indexes
+CREATE UNIQUE INDEX IF NOT EXISTS unique_room_number_per_hotel ON rooms(hotel_id, number) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_rooms_hotel_id ON rooms(hotel_id);
+CREATE INDEX IF NOT EXISTS idx_rooms_available ON rooms(available);
+CREATE INDEX IF NOT EXISTS idx_rooms_type ON rooms(type);
+CREATE INDEX IF NOT EXISTS idx_rooms_price ON rooms(price);
+CREATE INDEX IF NOT EXISTS idx_rooms_capacity ON rooms(capacity);
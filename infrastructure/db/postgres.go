@@ -1,10 +1,14 @@
 package db
 
 import (
+	"HotelService/application/dto"
 	"HotelService/domain/model"
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -33,13 +37,15 @@ func (r *HotelPostgresRepository) Save(ctx context.Context, hotel *model.Hotel)
 
 	now := time.Now()
 	query := `
-		INSERT INTO hotels (name, address, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4) 
+		INSERT INTO hotels (name, address, currency, owner_id, created_at, updated_at) 
+		VALUES ($1, $2, $3, $4, $5, $6) 
 		RETURNING id`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
 		hotel.Name,
 		hotel.Address,
+		hotel.Currency,
+		sql.NullInt64{Int64: hotel.OwnerID, Valid: hotel.OwnerID != 0},
 		now,
 		now,
 	).Scan(&hotel.ID)
@@ -50,6 +56,7 @@ func (r *HotelPostgresRepository) Save(ctx context.Context, hotel *model.Hotel)
 
 	hotel.CreatedAt = now
 	hotel.UpdatedAt = now
+	hotel.Version = 1
 	return nil
 }
 
@@ -63,14 +70,15 @@ func (r *HotelPostgresRepository) Update(ctx context.Context, hotel *model.Hotel
 
 	query := `
 		UPDATE hotels 
-		SET name = $1, address = $2, updated_at = $3 
-		WHERE id = $4`
+		SET name = $1, address = $2, updated_at = $3, version = version + 1 
+		WHERE id = $4 AND version = $5 AND deleted_at IS NULL`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
 		hotel.Name,
 		hotel.Address,
 		time.Now(),
 		hotel.ID,
+		hotel.Version,
 	)
 
 	if err != nil {
@@ -83,42 +91,35 @@ func (r *HotelPostgresRepository) Update(ctx context.Context, hotel *model.Hotel
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("hotel with ID %d not found", hotel.ID)
+		return staleOrMissing(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM hotels WHERE id = $1 AND deleted_at IS NULL)`, "hotel", hotel.ID, hotel.Version)
 	}
 
 	hotel.UpdatedAt = time.Now()
+	hotel.Version++
 	return nil
 }
 
 func (r *HotelPostgresRepository) FindByID(ctx context.Context, id int64) (*model.Hotel, error) {
 	query := `
-		SELECT id, name, address, created_at, updated_at 
+		SELECT id, name, address, currency, owner_id, version, created_at, updated_at, deleted_at 
 		FROM hotels 
-		WHERE id = $1`
-
-	hotel := &model.Hotel{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&hotel.ID,
-		&hotel.Name,
-		&hotel.Address,
-		&hotel.CreatedAt,
-		&hotel.UpdatedAt,
-	)
+		WHERE id = $1 AND deleted_at IS NULL`
 
+	hotel, err := scanHotel(conn(ctx, r.db).QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("hotel with ID %d not found", id)
+			return nil, model.NewNotFoundError("hotel with ID %d not found", id)
 		}
 		return nil, fmt.Errorf("failed to find hotel: %w", err)
 	}
 
 	roomsQuery := `
-		SELECT id, hotel_id, number, type, price, available, created_at, updated_at
+		SELECT id, hotel_id, number, type, price, capacity, available, created_at, updated_at, version
 		FROM rooms
-		WHERE hotel_id = $1
+		WHERE hotel_id = $1 AND deleted_at IS NULL
 		ORDER BY number`
 
-	rows, err := r.db.QueryContext(ctx, roomsQuery, id)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, roomsQuery, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load rooms: %w", err)
 	}
@@ -127,9 +128,13 @@ func (r *HotelPostgresRepository) FindByID(ctx context.Context, id int64) (*mode
 	var rooms []model.Room
 	for rows.Next() {
 		var room model.Room
-		if err := rows.Scan(&room.ID, &room.HotelID, &room.Number, &room.Type, &room.Price, &room.Available, &room.CreatedAt, &room.UpdatedAt); err != nil {
+		var price string
+		if err := rows.Scan(&room.ID, &room.HotelID, &room.Number, &room.Type, &price, &room.Capacity, &room.Available, &room.CreatedAt, &room.UpdatedAt, &room.Version); err != nil {
 			return nil, fmt.Errorf("failed to scan room: %w", err)
 		}
+		if room.Price, err = parsePrice(price, hotel.Currency); err != nil {
+			return nil, err
+		}
 		rooms = append(rooms, room)
 	}
 
@@ -143,37 +148,149 @@ func (r *HotelPostgresRepository) FindByID(ctx context.Context, id int64) (*mode
 
 func (r *HotelPostgresRepository) FindAll(ctx context.Context) ([]*model.Hotel, error) {
 	query := `
-		SELECT id, name, address, created_at, updated_at 
+		SELECT id, name, address, currency, owner_id, version, created_at, updated_at, deleted_at 
 		FROM hotels 
+		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find all hotels: %w", err)
 	}
 	defer rows.Close()
 
-	var hotels []*model.Hotel
-	for rows.Next() {
-		hotel := &model.Hotel{}
-		if err := rows.Scan(&hotel.ID, &hotel.Name, &hotel.Address, &hotel.CreatedAt, &hotel.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan hotel: %w", err)
+	return scanHotels(rows)
+}
+
+var hotelSorts = map[string]struct {
+	keyset
+	values func(hotel *model.Hotel) []string
+}{
+	"created_at": {
+		keyset{columns: []string{"created_at", "id"}},
+		func(hotel *model.Hotel) []string {
+			return []string{hotel.CreatedAt.Format(time.RFC3339Nano), strconv.FormatInt(hotel.ID, 10)}
+		},
+	},
+	"-created_at": {
+		keyset{columns: []string{"created_at", "id"}, desc: true},
+		func(hotel *model.Hotel) []string {
+			return []string{hotel.CreatedAt.Format(time.RFC3339Nano), strconv.FormatInt(hotel.ID, 10)}
+		},
+	},
+	"name": {
+		keyset{columns: []string{"name", "id"}},
+		func(hotel *model.Hotel) []string {
+			return []string{hotel.Name, strconv.FormatInt(hotel.ID, 10)}
+		},
+	},
+	"-name": {
+		keyset{columns: []string{"name", "id"}, desc: true},
+		func(hotel *model.Hotel) []string {
+			return []string{hotel.Name, strconv.FormatInt(hotel.ID, 10)}
+		},
+	},
+}
+
+// FindPage returns one page of hotels matching the filter plus the cursor of the next page.
+// An empty sort lists the newest hotels first.
+func (r *HotelPostgresRepository) FindPage(ctx context.Context, filter dto.HotelFilter) ([]*model.Hotel, string, error) {
+	sortKey := filter.Sort
+	if sortKey == "" {
+		sortKey = "-created_at"
+	}
+	sort, ok := hotelSorts[sortKey]
+	if !ok {
+		return nil, "", model.NewValidationError("unsupported sort %q", filter.Sort)
+	}
+
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+
+	if filter.Name != "" {
+		args = append(args, likePattern(filter.Name))
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+	if filter.Address != "" {
+		args = append(args, likePattern(filter.Address))
+		conditions = append(conditions, fmt.Sprintf("address ILIKE $%d", len(args)))
+	}
+	if filter.Cursor != "" {
+		values, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
 		}
-		hotels = append(hotels, hotel)
+		condition, err := sort.after(values, &args)
+		if err != nil {
+			return nil, "", err
+		}
+		conditions = append(conditions, condition)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating hotels: %w", err)
+	args = append(args, filter.Limit+1)
+	query := `
+		SELECT id, name, address, currency, owner_id, version, created_at, updated_at, deleted_at
+		FROM hotels
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + sort.orderBy() + fmt.Sprintf(`
+		LIMIT $%d`, len(args))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find hotels: %w", err)
 	}
+	defer rows.Close()
 
-	return hotels, nil
+	hotels, err := scanHotels(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(hotels) > filter.Limit {
+		hotels = hotels[:filter.Limit]
+		nextCursor = encodeCursor(sort.values(hotels[len(hotels)-1]))
+	}
+
+	return hotels, nextCursor, nil
 }
 
+// Delete removes the hotel for good, cascading to its rooms, rate plans and
+// staff, and deletes the reservations of its rooms, which fk_room would
+// otherwise restrict. Run it in a unit of work so nothing is left half deleted.
+// The API soft-deletes with SoftDelete instead.
 func (r *HotelPostgresRepository) Delete(ctx context.Context, id int64) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM reservations WHERE room_id IN (SELECT id FROM rooms WHERE hotel_id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete reservations: %w", err)
+	}
 
 	query := `DELETE FROM hotels WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete hotel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.NewNotFoundError("hotel with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// SoftDelete hides the hotel and its rooms from every query. Run it in a unit
+// of work so the hotel and its rooms are hidden together.
+func (r *HotelPostgresRepository) SoftDelete(ctx context.Context, id, version int64) error {
+	now := time.Now()
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE hotels SET deleted_at = $1, version = version + 1
+		WHERE id = $2 AND deleted_at IS NULL AND ($3 = 0 OR version = $3)`, now, id, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete hotel: %w", err)
 	}
@@ -184,12 +301,126 @@ func (r *HotelPostgresRepository) Delete(ctx context.Context, id int64) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("hotel with ID %d not found", id)
+		return staleOrMissing(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM hotels WHERE id = $1 AND deleted_at IS NULL)`, "hotel", id, version)
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, `UPDATE rooms SET deleted_at = $1 WHERE hotel_id = $2 AND deleted_at IS NULL`, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete rooms: %w", err)
 	}
 
 	return nil
 }
 
+// FindDeletedByID loads a soft-deleted hotel without its rooms
+func (r *HotelPostgresRepository) FindDeletedByID(ctx context.Context, id int64) (*model.Hotel, error) {
+	query := `
+		SELECT id, name, address, currency, owner_id, version, created_at, updated_at, deleted_at
+		FROM hotels
+		WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	hotel, err := scanHotel(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("deleted hotel with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to find deleted hotel: %w", err)
+	}
+
+	return hotel, nil
+}
+
+// Restore brings back a soft-deleted hotel together with the rooms deleted
+// with it, which share its deleted_at; rooms deleted before the hotel stay
+// deleted. Run it in a unit of work like SoftDelete.
+func (r *HotelPostgresRepository) Restore(ctx context.Context, id int64) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE rooms SET deleted_at = NULL
+		WHERE hotel_id = $1 AND deleted_at = (SELECT deleted_at FROM hotels WHERE id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore rooms: %w", err)
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE hotels SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore hotel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.NewNotFoundError("deleted hotel with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// BumpVersion marks the hotel changed after a change of its rooms
+func (r *HotelPostgresRepository) BumpVersion(ctx context.Context, id int64) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE hotels SET version = version + 1, updated_at = $1 WHERE id = $2 AND deleted_at IS NULL`, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update hotel version: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.NewNotFoundError("hotel with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// purgeableHotels selects the hotels soft-deleted before $1 none of whose rooms
+// has a reservation in status $2 ending after $3
+const purgeableHotels = `
+	SELECT h.id
+	FROM hotels h
+	WHERE h.deleted_at < $1
+	  AND NOT EXISTS (
+		SELECT 1
+		FROM reservations res
+		JOIN rooms r ON r.id = res.room_id
+		WHERE r.hotel_id = h.id AND res.status = $2 AND res.check_out > $3
+	  )`
+
+// PurgeDeleted removes hotels soft-deleted before the cutoff for good and
+// returns them. Their reservations are all cancelled or past, and are removed
+// first since fk_room restricts taking them along; run it in a unit of work.
+func (r *HotelPostgresRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]*model.Hotel, error) {
+	args := []interface{}{before, model.ReservationStatusConfirmed, today()}
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		DELETE FROM reservations
+		WHERE room_id IN (SELECT id FROM rooms WHERE hotel_id IN (`+purgeableHotels+`))`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge reservations: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		DELETE FROM hotels
+		WHERE id IN (`+purgeableHotels+`)
+		RETURNING id, name, address, currency, owner_id, version, created_at, updated_at, deleted_at`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge deleted hotels: %w", err)
+	}
+	defer rows.Close()
+
+	hotels, err := scanHotels(rows)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(hotels, func(i, j int) bool { return hotels[i].ID < hotels[j].ID })
+
+	return hotels, nil
+}
+
 // RoomPostgresRepository
 
 func (r *RoomPostgresRepository) Save(ctx context.Context, room *model.Room) error {
@@ -198,27 +429,35 @@ func (r *RoomPostgresRepository) Save(ctx context.Context, room *model.Room) err
 	}
 
 	query := `
-		INSERT INTO rooms (hotel_id, number, type, price, available, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO rooms (hotel_id, number, type, price, capacity, available, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id`
 
 	now := time.Now()
-	err := r.db.QueryRowContext(ctx, query,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
 		room.HotelID,
 		room.Number,
 		room.Type,
-		room.Price,
+		room.Price.Decimal(),
+		room.Capacity,
 		room.Available,
 		now,
 		now,
 	).Scan(&room.ID)
 
 	if err != nil {
+		if isConstraintViolation(err, pgUniqueViolation, "unique_room_number_per_hotel") {
+			return model.NewConflictError("room %s already exists in hotel %d", room.Number, room.HotelID)
+		}
+		if isConstraintViolation(err, pgForeignKeyViolation, "fk_hotel") {
+			return model.NewNotFoundError("hotel with ID %d not found", room.HotelID)
+		}
 		return fmt.Errorf("failed to save room: %w", err)
 	}
 
 	room.CreatedAt = now
 	room.UpdatedAt = now
+	room.Version = 1
 	return nil
 }
 
@@ -232,20 +471,25 @@ func (r *RoomPostgresRepository) Update(ctx context.Context, room *model.Room) e
 
 	query := `
 		UPDATE rooms
-		SET hotel_id = $1, number = $2, type = $3, price = $4, available = $5, updated_at = $6
-		WHERE id = $7`
+		SET hotel_id = $1, number = $2, type = $3, price = $4, capacity = $5, available = $6, updated_at = $7, version = version + 1
+		WHERE id = $8 AND version = $9 AND deleted_at IS NULL`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
 		room.HotelID,
 		room.Number,
 		room.Type,
-		room.Price,
+		room.Price.Decimal(),
+		room.Capacity,
 		room.Available,
 		time.Now(),
 		room.ID,
+		room.Version,
 	)
 
 	if err != nil {
+		if isConstraintViolation(err, pgUniqueViolation, "unique_room_number_per_hotel") {
+			return model.NewConflictError("room %s already exists in hotel %d", room.Number, room.HotelID)
+		}
 		return fmt.Errorf("failed to update room: %w", err)
 	}
 
@@ -255,34 +499,25 @@ func (r *RoomPostgresRepository) Update(ctx context.Context, room *model.Room) e
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("room with ID %d not found", room.ID)
+		return staleOrMissing(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM rooms WHERE id = $1 AND deleted_at IS NULL)`, "room", room.ID, room.Version)
 	}
 
 	room.UpdatedAt = time.Now()
+	room.Version++
 	return nil
 }
 
 func (r *RoomPostgresRepository) FindByID(ctx context.Context, id int64) (*model.Room, error) {
 	query := `
-		SELECT id, hotel_id, number, type, price, available, created_at, updated_at
-		FROM rooms
-		WHERE id = $1`
-
-	room := &model.Room{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&room.ID,
-		&room.HotelID,
-		&room.Number,
-		&room.Type,
-		&room.Price,
-		&room.Available,
-		&room.CreatedAt,
-		&room.UpdatedAt,
-	)
+		SELECT r.id, r.hotel_id, r.number, r.type, r.price, h.currency, r.capacity, r.available, r.created_at, r.updated_at, r.version
+		FROM rooms r
+		JOIN hotels h ON h.id = r.hotel_id
+		WHERE r.id = $1 AND r.deleted_at IS NULL`
 
+	room, err := scanRoom(conn(ctx, r.db).QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("room with ID %d not found", id)
+			return nil, model.NewNotFoundError("room with ID %d not found", id)
 		}
 		return nil, fmt.Errorf("failed to find room: %w", err)
 	}
@@ -292,55 +527,186 @@ func (r *RoomPostgresRepository) FindByID(ctx context.Context, id int64) (*model
 
 func (r *RoomPostgresRepository) FindAll(ctx context.Context) ([]*model.Room, error) {
 	query := `
-		SELECT id, hotel_id, number, type, price, available, created_at, updated_at
-		FROM rooms
-		ORDER BY hotel_id, number`
+		SELECT r.id, r.hotel_id, r.number, r.type, r.price, h.currency, r.capacity, r.available, r.created_at, r.updated_at, r.version
+		FROM rooms r
+		JOIN hotels h ON h.id = r.hotel_id
+		WHERE r.deleted_at IS NULL
+		ORDER BY r.hotel_id, r.number`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find all rooms: %w", err)
 	}
 	defer rows.Close()
 
-	return r.scanRooms(rows)
+	return scanRooms(rows)
 }
 
 func (r *RoomPostgresRepository) FindByHotelID(ctx context.Context, hotelID int64) ([]*model.Room, error) {
 	query := `
-		SELECT id, hotel_id, number, type, price, available, created_at, updated_at
-		FROM rooms
-		WHERE hotel_id = $1
-		ORDER BY number`
+		SELECT r.id, r.hotel_id, r.number, r.type, r.price, h.currency, r.capacity, r.available, r.created_at, r.updated_at, r.version
+		FROM rooms r
+		JOIN hotels h ON h.id = r.hotel_id
+		WHERE r.hotel_id = $1 AND r.deleted_at IS NULL
+		ORDER BY r.number`
 
-	rows, err := r.db.QueryContext(ctx, query, hotelID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, hotelID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find rooms by hotel ID: %w", err)
 	}
 	defer rows.Close()
 
-	return r.scanRooms(rows)
+	return scanRooms(rows)
 }
 
 func (r *RoomPostgresRepository) FindAllAvailable(ctx context.Context) ([]*model.Room, error) {
 	query := `
-		SELECT id, hotel_id, number, type, price, available, created_at, updated_at
-		FROM rooms
-		WHERE available = true
-		ORDER BY hotel_id, number`
+		SELECT r.id, r.hotel_id, r.number, r.type, r.price, h.currency, r.capacity, r.available, r.created_at, r.updated_at, r.version
+		FROM rooms r
+		JOIN hotels h ON h.id = r.hotel_id
+		WHERE r.available = true AND r.deleted_at IS NULL
+		ORDER BY r.hotel_id, r.number`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find available rooms: %w", err)
 	}
 	defer rows.Close()
 
-	return r.scanRooms(rows)
+	return scanRooms(rows)
 }
 
-func (r *RoomPostgresRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM rooms WHERE id = $1`
+var roomSorts = map[string]struct {
+	keyset
+	values func(room *model.Room) []string
+}{
+	"": {
+		keyset{columns: []string{"r.hotel_id", "r.number", "r.id"}},
+		func(room *model.Room) []string {
+			return []string{strconv.FormatInt(room.HotelID, 10), room.Number, strconv.FormatInt(room.ID, 10)}
+		},
+	},
+	"price": {
+		keyset{columns: []string{"r.price", "r.id"}},
+		func(room *model.Room) []string {
+			return []string{room.Price.Decimal(), strconv.FormatInt(room.ID, 10)}
+		},
+	},
+	"-price": {
+		keyset{columns: []string{"r.price", "r.id"}, desc: true},
+		func(room *model.Room) []string {
+			return []string{room.Price.Decimal(), strconv.FormatInt(room.ID, 10)}
+		},
+	},
+	"capacity": {
+		keyset{columns: []string{"r.capacity", "r.id"}},
+		func(room *model.Room) []string {
+			return []string{strconv.Itoa(room.Capacity), strconv.FormatInt(room.ID, 10)}
+		},
+	},
+	"-capacity": {
+		keyset{columns: []string{"r.capacity", "r.id"}, desc: true},
+		func(room *model.Room) []string {
+			return []string{strconv.Itoa(room.Capacity), strconv.FormatInt(room.ID, 10)}
+		},
+	},
+}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+// FindAvailableForStay returns one page of open rooms that match the search and have no
+// confirmed reservation overlapping the requested stay, plus the cursor of the next page
+func (r *RoomPostgresRepository) FindAvailableForStay(ctx context.Context, search dto.StaySearch) ([]*model.Room, string, error) {
+	sort, ok := roomSorts[search.Sort]
+	if !ok {
+		return nil, "", model.NewValidationError("unsupported sort %q", search.Sort)
+	}
+
+	conditions := []string{"r.available = true", "r.deleted_at IS NULL"}
+	var args []interface{}
+
+	if search.HotelID > 0 {
+		args = append(args, search.HotelID)
+		conditions = append(conditions, fmt.Sprintf("r.hotel_id = $%d", len(args)))
+	}
+	if search.Guests > 0 {
+		args = append(args, search.Guests)
+		conditions = append(conditions, fmt.Sprintf("r.capacity >= $%d", len(args)))
+	}
+	if search.Type != "" {
+		args = append(args, search.Type)
+		conditions = append(conditions, fmt.Sprintf("r.type = $%d", len(args)))
+	}
+	if search.MinPrice != nil {
+		args = append(args, search.MinPrice.Currency, search.MinPrice.Decimal())
+		conditions = append(conditions, fmt.Sprintf("h.currency = $%d AND r.price >= $%d", len(args)-1, len(args)))
+	}
+	if search.MaxPrice != nil {
+		args = append(args, search.MaxPrice.Currency, search.MaxPrice.Decimal())
+		conditions = append(conditions, fmt.Sprintf("h.currency = $%d AND r.price <= $%d", len(args)-1, len(args)))
+	}
+	if !search.CheckIn.IsZero() && !search.CheckOut.IsZero() {
+		args = append(args, model.ReservationStatusConfirmed, search.CheckIn, search.CheckOut)
+		conditions = append(conditions, fmt.Sprintf(`NOT EXISTS (
+			SELECT 1
+			FROM reservations res
+			WHERE res.room_id = r.id
+			  AND res.status = $%d
+			  AND res.check_in < $%d
+			  AND res.check_out > $%d
+		)`, len(args)-2, len(args), len(args)-1))
+	}
+	if search.Cursor != "" {
+		values, err := decodeCursor(search.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		condition, err := sort.after(values, &args)
+		if err != nil {
+			return nil, "", err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	args = append(args, search.Limit+1)
+	query := `
+		SELECT r.id, r.hotel_id, r.number, r.type, r.price, h.currency, r.capacity, r.available, r.created_at, r.updated_at, r.version
+		FROM rooms r
+		JOIN hotels h ON h.id = r.hotel_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + sort.orderBy() + fmt.Sprintf(`
+		LIMIT $%d`, len(args))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find rooms for stay: %w", err)
+	}
+	defer rows.Close()
+
+	rooms, err := scanRooms(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(rooms) > search.Limit {
+		rooms = rooms[:search.Limit]
+		nextCursor = encodeCursor(sort.values(rooms[len(rooms)-1]))
+	}
+
+	return rooms, nextCursor, nil
+}
+
+// Delete soft-deletes the room, keeping its reservations on record. It fails
+// with ErrConflict while a confirmed stay has yet to end.
+func (r *RoomPostgresRepository) Delete(ctx context.Context, id, version int64) error {
+	query := `
+		UPDATE rooms SET deleted_at = $3, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)
+		  AND NOT EXISTS (
+			SELECT 1 FROM reservations
+			WHERE room_id = $1 AND status = $4 AND check_out > $5
+		  )`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, version, time.Now(), model.ReservationStatusConfirmed, today())
 	if err != nil {
 		return fmt.Errorf("failed to delete room: %w", err)
 	}
@@ -351,19 +717,27 @@ func (r *RoomPostgresRepository) Delete(ctx context.Context, id int64) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("room with ID %d not found", id)
+		// A current room at the expected version was kept by an upcoming stay
+		current, err := exists(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM rooms WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2))`, id, version)
+		if err != nil {
+			return err
+		}
+		if current {
+			return model.NewConflictError("room %d has upcoming reservations", id)
+		}
+		return staleOrMissing(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM rooms WHERE id = $1 AND deleted_at IS NULL)`, "room", id, version)
 	}
 
 	return nil
 }
 
-func (r *RoomPostgresRepository) UpdateAvailability(ctx context.Context, id int64, available bool) error {
+func (r *RoomPostgresRepository) UpdateAvailability(ctx context.Context, id int64, available bool, version int64) error {
 	query := `
 		UPDATE rooms 
-		SET available = $1, updated_at = $2 
-		WHERE id = $3`
+		SET available = $1, updated_at = $2, version = version + 1
+		WHERE id = $3 AND version = $4 AND deleted_at IS NULL`
 
-	result, err := r.db.ExecContext(ctx, query, available, time.Now(), id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, available, time.Now(), id, version)
 	if err != nil {
 		return fmt.Errorf("failed to update room availability: %w", err)
 	}
@@ -374,18 +748,36 @@ func (r *RoomPostgresRepository) UpdateAvailability(ctx context.Context, id int6
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("room with ID %d not found", id)
+		return staleOrMissing(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM rooms WHERE id = $1 AND deleted_at IS NULL)`, "room", id, version)
 	}
 
 	return nil
 }
 
+// Repeatable hotel rows scan
+func scanHotels(rows *sql.Rows) ([]*model.Hotel, error) {
+	var hotels []*model.Hotel
+	for rows.Next() {
+		hotel, err := scanHotel(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan hotel: %w", err)
+		}
+		hotels = append(hotels, hotel)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating hotels: %w", err)
+	}
+
+	return hotels, nil
+}
+
 // Repeatable room rows scan
-func (r *RoomPostgresRepository) scanRooms(rows *sql.Rows) ([]*model.Room, error) {
+func scanRooms(rows *sql.Rows) ([]*model.Room, error) {
 	var rooms []*model.Room
 	for rows.Next() {
-		room := &model.Room{}
-		if err := rows.Scan(&room.ID, &room.HotelID, &room.Number, &room.Type, &room.Price, &room.Available, &room.CreatedAt, &room.UpdatedAt); err != nil {
+		room, err := scanRoom(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan room: %w", err)
 		}
 		rooms = append(rooms, room)
@@ -397,3 +789,46 @@ func (r *RoomPostgresRepository) scanRooms(rows *sql.Rows) ([]*model.Room, error
 
 	return rooms, nil
 }
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanHotel(row rowScanner) (*model.Hotel, error) {
+	hotel := &model.Hotel{}
+	var ownerID sql.NullInt64
+	var deletedAt sql.NullTime
+	if err := row.Scan(&hotel.ID, &hotel.Name, &hotel.Address, &hotel.Currency, &ownerID, &hotel.Version, &hotel.CreatedAt, &hotel.UpdatedAt, &deletedAt); err != nil {
+		return nil, err
+	}
+	hotel.OwnerID = ownerID.Int64
+	if deletedAt.Valid {
+		hotel.DeletedAt = &deletedAt.Time
+	}
+	return hotel, nil
+}
+
+// scanRoom reads a room row whose price column is followed by the hotel currency
+func scanRoom(row rowScanner) (*model.Room, error) {
+	room := &model.Room{}
+	var price, currency string
+	if err := row.Scan(&room.ID, &room.HotelID, &room.Number, &room.Type, &price, &currency, &room.Capacity, &room.Available, &room.CreatedAt, &room.UpdatedAt, &room.Version); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if room.Price, err = parsePrice(price, currency); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// parsePrice converts a DECIMAL column read as text into Money
+func parsePrice(decimal, currency string) (model.Money, error) {
+	price, err := model.ParseMoney(decimal, currency)
+	if err != nil {
+		return model.Money{}, fmt.Errorf("invalid stored price: %w", err)
+	}
+	return price, nil
+}
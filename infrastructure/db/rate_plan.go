@@ -0,0 +1,239 @@
+package db
+
+import (
+	"HotelService/domain/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type RatePlanPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewRatePlanRepository(db *sql.DB) *RatePlanPostgresRepository {
+	return &RatePlanPostgresRepository{db: db}
+}
+
+func (r *RatePlanPostgresRepository) Save(ctx context.Context, plan *model.RatePlan) error {
+	if plan == nil {
+		return fmt.Errorf("rate plan cannot be nil")
+	}
+
+	query := `
+		INSERT INTO rate_plans (hotel_id, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	now := time.Now()
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		plan.HotelID,
+		plan.Name,
+		now,
+		now,
+	).Scan(&plan.ID)
+
+	if err != nil {
+		if isConstraintViolation(err, pgUniqueViolation, "unique_rate_plan_name_per_hotel") {
+			return model.NewConflictError("rate plan %q already exists in hotel %d", plan.Name, plan.HotelID)
+		}
+		if isConstraintViolation(err, pgForeignKeyViolation, "fk_rate_plan_hotel") {
+			return model.NewNotFoundError("hotel with ID %d not found", plan.HotelID)
+		}
+		return fmt.Errorf("failed to save rate plan: %w", err)
+	}
+
+	plan.CreatedAt = now
+	plan.UpdatedAt = now
+	return nil
+}
+
+// FindByID loads the plan together with its seasonal rates and day modifiers
+func (r *RatePlanPostgresRepository) FindByID(ctx context.Context, id int64) (*model.RatePlan, error) {
+	query := `
+		SELECT id, hotel_id, name, created_at, updated_at
+		FROM rate_plans
+		WHERE id = $1`
+
+	plan := &model.RatePlan{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&plan.ID,
+		&plan.HotelID,
+		&plan.Name,
+		&plan.CreatedAt,
+		&plan.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("rate plan with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to find rate plan: %w", err)
+	}
+
+	if plan.SeasonalRates, err = r.findSeasonalRates(ctx, id); err != nil {
+		return nil, err
+	}
+	if plan.DayModifiers, err = r.findDayModifiers(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (r *RatePlanPostgresRepository) FindByHotelID(ctx context.Context, hotelID int64) ([]*model.RatePlan, error) {
+	query := `
+		SELECT id, hotel_id, name, created_at, updated_at
+		FROM rate_plans
+		WHERE hotel_id = $1
+		ORDER BY name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rate plans by hotel ID: %w", err)
+	}
+	defer rows.Close()
+
+	var plans []*model.RatePlan
+	for rows.Next() {
+		plan := &model.RatePlan{}
+		if err := rows.Scan(&plan.ID, &plan.HotelID, &plan.Name, &plan.CreatedAt, &plan.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan rate plan: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rate plans: %w", err)
+	}
+
+	return plans, nil
+}
+
+func (r *RatePlanPostgresRepository) SaveSeasonalRate(ctx context.Context, rate *model.SeasonalRate) error {
+	if rate == nil {
+		return fmt.Errorf("seasonal rate cannot be nil")
+	}
+
+	query := `
+		INSERT INTO seasonal_rates (rate_plan_id, room_id, room_type, start_date, end_date, price, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	roomType := sql.NullString{String: rate.RoomType, Valid: rate.RoomType != ""}
+
+	now := time.Now()
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		rate.RatePlanID,
+		rate.RoomID,
+		roomType,
+		rate.StartDate,
+		rate.EndDate,
+		rate.Price.Decimal(),
+		now,
+	).Scan(&rate.ID)
+
+	if err != nil {
+		if isConstraintViolation(err, pgForeignKeyViolation, "fk_seasonal_rate_plan") {
+			return model.NewNotFoundError("rate plan with ID %d not found", rate.RatePlanID)
+		}
+		if isConstraintViolation(err, pgForeignKeyViolation, "fk_seasonal_rate_room") {
+			return model.NewNotFoundError("room with ID %d not found", *rate.RoomID)
+		}
+		return fmt.Errorf("failed to save seasonal rate: %w", err)
+	}
+
+	rate.CreatedAt = now
+	return nil
+}
+
+// SetDayModifier creates or replaces the modifier for the weekday
+func (r *RatePlanPostgresRepository) SetDayModifier(ctx context.Context, ratePlanID int64, modifier model.DayModifier) error {
+	query := `
+		INSERT INTO rate_plan_day_modifiers (rate_plan_id, weekday, multiplier)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (rate_plan_id, weekday) DO UPDATE SET multiplier = EXCLUDED.multiplier`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, ratePlanID, int(modifier.Weekday), modifier.Multiplier)
+	if err != nil {
+		if isConstraintViolation(err, pgForeignKeyViolation, "fk_day_modifier_plan") {
+			return model.NewNotFoundError("rate plan with ID %d not found", ratePlanID)
+		}
+		return fmt.Errorf("failed to set day modifier: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RatePlanPostgresRepository) findSeasonalRates(ctx context.Context, ratePlanID int64) ([]model.SeasonalRate, error) {
+	query := `
+		SELECT sr.id, sr.rate_plan_id, sr.room_id, sr.room_type, sr.start_date, sr.end_date, sr.price, h.currency, sr.created_at
+		FROM seasonal_rates sr
+		JOIN rate_plans rp ON rp.id = sr.rate_plan_id
+		JOIN hotels h ON h.id = rp.hotel_id
+		WHERE sr.rate_plan_id = $1
+		ORDER BY sr.id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, ratePlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load seasonal rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []model.SeasonalRate
+	for rows.Next() {
+		var rate model.SeasonalRate
+		var roomID sql.NullInt64
+		var roomType sql.NullString
+		var price, currency string
+		if err := rows.Scan(&rate.ID, &rate.RatePlanID, &roomID, &roomType, &rate.StartDate, &rate.EndDate, &price, &currency, &rate.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan seasonal rate: %w", err)
+		}
+		if rate.Price, err = parsePrice(price, currency); err != nil {
+			return nil, err
+		}
+		if roomID.Valid {
+			rate.RoomID = &roomID.Int64
+		}
+		rate.RoomType = roomType.String
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating seasonal rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+func (r *RatePlanPostgresRepository) findDayModifiers(ctx context.Context, ratePlanID int64) ([]model.DayModifier, error) {
+	query := `
+		SELECT weekday, multiplier
+		FROM rate_plan_day_modifiers
+		WHERE rate_plan_id = $1
+		ORDER BY weekday`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, ratePlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load day modifiers: %w", err)
+	}
+	defer rows.Close()
+
+	var modifiers []model.DayModifier
+	for rows.Next() {
+		var modifier model.DayModifier
+		var weekday int
+		if err := rows.Scan(&weekday, &modifier.Multiplier); err != nil {
+			return nil, fmt.Errorf("failed to scan day modifier: %w", err)
+		}
+		modifier.Weekday = time.Weekday(weekday)
+		modifiers = append(modifiers, modifier)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating day modifiers: %w", err)
+	}
+
+	return modifiers, nil
+}
@@ -0,0 +1,21 @@
+package db
+
+import (
+	"HotelService/application/service"
+	"database/sql"
+)
+
+// NewRepositories returns the Postgres implementation of every repository
+func NewRepositories(db *sql.DB) service.Repositories {
+	return service.Repositories{
+		UnitOfWork:   NewUnitOfWork(db),
+		Hotels:       NewHotelRepository(db),
+		Rooms:        NewRoomRepository(db),
+		Reservations: NewReservationRepository(db),
+		RatePlans:    NewRatePlanRepository(db),
+		Hoteliers:    NewHotelierRepository(db),
+		Staff:        NewStaffRepository(db),
+		Audit:        NewAuditRepository(db),
+		Idempotency:  NewIdempotencyRepository(db),
+	}
+}
@@ -0,0 +1,213 @@
+package db
+
+import (
+	"HotelService/domain/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type ReservationPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewReservationRepository(db *sql.DB) *ReservationPostgresRepository {
+	return &ReservationPostgresRepository{db: db}
+}
+
+func (r *ReservationPostgresRepository) Save(ctx context.Context, reservation *model.Reservation) error {
+	if reservation == nil {
+		return fmt.Errorf("reservation cannot be nil")
+	}
+
+	query := `
+		INSERT INTO reservations (room_id, code, guest_name, guest_email, check_in, check_out, status, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`
+
+	now := time.Now()
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		reservation.RoomID,
+		reservation.Code,
+		reservation.GuestName,
+		reservation.GuestEmail,
+		reservation.CheckIn,
+		reservation.CheckOut,
+		reservation.Status,
+		now,
+		now,
+	).Scan(&reservation.ID)
+
+	if err != nil {
+		if isConstraintViolation(err, pgExclusionViolation, "no_overlapping_reservations") {
+			return model.ErrReservationOverlap
+		}
+		return fmt.Errorf("failed to save reservation: %w", err)
+	}
+
+	reservation.CreatedAt = now
+	reservation.UpdatedAt = now
+	return nil
+}
+
+func (r *ReservationPostgresRepository) FindByID(ctx context.Context, id int64) (*model.Reservation, error) {
+	query := `
+		SELECT id, room_id, COALESCE(code, ''), guest_name, guest_email, check_in, check_out, status, created_at, updated_at
+		FROM reservations
+		WHERE id = $1`
+
+	reservation := &model.Reservation{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&reservation.ID,
+		&reservation.RoomID,
+		&reservation.Code,
+		&reservation.GuestName,
+		&reservation.GuestEmail,
+		&reservation.CheckIn,
+		&reservation.CheckOut,
+		&reservation.Status,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("reservation with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to find reservation: %w", err)
+	}
+
+	return reservation, nil
+}
+
+// FindByCode returns the reservation the guest was given code for
+func (r *ReservationPostgresRepository) FindByCode(ctx context.Context, code string) (*model.Reservation, error) {
+	query := `
+		SELECT id, room_id, COALESCE(code, ''), guest_name, guest_email, check_in, check_out, status, created_at, updated_at
+		FROM reservations
+		WHERE code = $1`
+
+	reservation := &model.Reservation{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, code).Scan(
+		&reservation.ID,
+		&reservation.RoomID,
+		&reservation.Code,
+		&reservation.GuestName,
+		&reservation.GuestEmail,
+		&reservation.CheckIn,
+		&reservation.CheckOut,
+		&reservation.Status,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("reservation not found")
+		}
+		return nil, fmt.Errorf("failed to find reservation: %w", err)
+	}
+
+	return reservation, nil
+}
+
+func (r *ReservationPostgresRepository) FindByRoomID(ctx context.Context, roomID int64) ([]*model.Reservation, error) {
+	query := `
+		SELECT id, room_id, COALESCE(code, ''), guest_name, guest_email, check_in, check_out, status, created_at, updated_at
+		FROM reservations
+		WHERE room_id = $1
+		ORDER BY check_in`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reservations by room ID: %w", err)
+	}
+	defer rows.Close()
+
+	var reservations []*model.Reservation
+	for rows.Next() {
+		reservation := &model.Reservation{}
+		if err := rows.Scan(&reservation.ID, &reservation.RoomID, &reservation.Code, &reservation.GuestName, &reservation.GuestEmail, &reservation.CheckIn, &reservation.CheckOut, &reservation.Status, &reservation.CreatedAt, &reservation.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// HasOverlap reports whether a confirmed reservation for the room intersects [checkIn, checkOut)
+func (r *ReservationPostgresRepository) HasOverlap(ctx context.Context, roomID int64, checkIn, checkOut time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM reservations
+			WHERE room_id = $1
+			  AND status = $2
+			  AND check_in < $4
+			  AND check_out > $3
+		)`
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, roomID, model.ReservationStatusConfirmed, checkIn, checkOut).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check reservation overlap: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *ReservationPostgresRepository) HasUpcomingStays(ctx context.Context, hotelID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM reservations res
+			JOIN rooms r ON r.id = res.room_id
+			WHERE r.hotel_id = $1
+			  AND res.status = $2
+			  AND res.check_out > $3
+		)`
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, hotelID, model.ReservationStatusConfirmed, today()).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check upcoming stays: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *ReservationPostgresRepository) UpdateStatus(ctx context.Context, id int64, status model.ReservationStatus) error {
+	query := `
+		UPDATE reservations
+		SET status = $1, updated_at = $2
+		WHERE id = $3`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, status, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update reservation status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.NewNotFoundError("reservation with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// today is the current date the way stay dates are stored: midnight UTC of the
+// local calendar day, as the service truncates them
+func today() time.Time {
+	y, m, d := time.Now().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,20 @@
+-- Sample hotels and rooms for local development and demos. Safe to run repeatedly.
+INSERT INTO hotels (name, address)
+SELECT v.name, v.address
+FROM (VALUES
+    ('Grand Hotel', 'Sudino, Glavnaya ul, 12'),
+    ('Ocean View Resort', '456 Beach Blvd, Miami, FL')
+) AS v(name, address)
+WHERE NOT EXISTS (SELECT 1 FROM hotels h WHERE h.name = v.name);
+
+INSERT INTO rooms (hotel_id, number, type, price, available)
+SELECT h.id, v.number, v.type, v.price, v.available
+FROM (VALUES
+    ('Grand Hotel', '101', 'Single', 100.00, true),
+    ('Grand Hotel', '102', 'Double', 150.00, true),
+    ('Grand Hotel', '103', 'Suite', 250.00, false),
+    ('Ocean View Resort', '201', 'Single', 120.00, true),
+    ('Ocean View Resort', '202', 'Double', 180.00, true)
+) AS v(hotel_name, number, type, price, available)
+JOIN hotels h ON h.name = v.hotel_name
+ON CONFLICT ON CONSTRAINT unique_room_number_per_hotel DO NOTHING;
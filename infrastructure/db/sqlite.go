@@ -0,0 +1,120 @@
+package db
+
+import (
+	"HotelService/application/service"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// SQLiteConfig locates the database of a single-property or offline deployment
+type SQLiteConfig struct {
+	// Path is the database file, created if missing; ":memory:" keeps the
+	// database in memory for the life of the process
+	Path string
+
+	// BusyTimeout is how long a statement waits for a lock held by another
+	// process, such as hotelctl, before failing
+	BusyTimeout time.Duration
+}
+
+// NewSQLiteDB opens the SQLite database at cfg.Path with foreign keys enforced.
+// Timestamps are written as text in the format the driver reads back, and
+// transactions take the write lock when they begin so they cannot deadlock
+// upgrading from a read.
+func NewSQLiteDB(cfg SQLiteConfig) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", cfg.BusyTimeout.Milliseconds()))
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_time_format", "sqlite")
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+cfg.Path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite allows one writer at a time. A single connection queues writers
+	// instead of failing them with SQLITE_BUSY, and keeps an in-memory database
+	// alive and shared by every query.
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+// NewSQLiteRepositories returns the SQLite implementation of every repository.
+// Units of work only rely on database/sql transactions and are shared with Postgres.
+func NewSQLiteRepositories(db *sql.DB) service.Repositories {
+	return service.Repositories{
+		UnitOfWork:   NewUnitOfWork(db),
+		Hotels:       NewHotelSQLiteRepository(db),
+		Rooms:        NewRoomSQLiteRepository(db),
+		Reservations: NewReservationSQLiteRepository(db),
+		RatePlans:    NewRatePlanSQLiteRepository(db),
+		Hoteliers:    NewHotelierSQLiteRepository(db),
+		Staff:        NewStaffSQLiteRepository(db),
+		Audit:        NewAuditSQLiteRepository(db),
+		Idempotency:  NewIdempotencySQLiteRepository(db),
+	}
+}
+
+// sqliteNow returns the current time in UTC. Timestamps are stored as text,
+// which only sorts chronologically when every value has the same offset.
+func sqliteNow() time.Time {
+	return time.Now().UTC()
+}
+
+// sqliteTimeFormat is how the driver writes time values with _time_format=sqlite
+const sqliteTimeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// sqliteDate formats t the way DATE columns are stored, mirroring the
+// truncation Postgres applies to DATE values
+func sqliteDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// sqliteAfter is keyset.after with SQLite's numbered parameters
+func (k keyset) sqliteAfter(values []string, args *[]interface{}) (string, error) {
+	condition, err := k.after(values, args)
+	return strings.ReplaceAll(condition, "$", "?"), err
+}
+
+// https://www.sqlite.org/rescode.html
+const (
+	sqliteConstraintCheck      = 275
+	sqliteConstraintForeignKey = 787
+	sqliteConstraintTrigger    = 1811
+	sqliteConstraintUnique     = 2067
+)
+
+// isSQLiteViolation reports whether err carries the extended result code and
+// its message contains detail. SQLite names the constraint in CHECK and
+// trigger errors, the columns in UNIQUE errors and nothing in foreign key errors.
+func isSQLiteViolation(err error, code int, detail string) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code() == code && strings.Contains(sqliteErr.Error(), detail)
+}
+
+// exists runs a SELECT EXISTS query; used to find which foreign key failed
+func exists(ctx context.Context, db *sql.DB, query string, args ...interface{}) (bool, error) {
+	var found bool
+	if err := conn(ctx, db).QueryRowContext(ctx, query, args...).Scan(&found); err != nil {
+		return false, fmt.Errorf("failed to check reference: %w", err)
+	}
+	return found, nil
+}
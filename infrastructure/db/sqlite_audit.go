@@ -0,0 +1,110 @@
+package db
+
+import (
+	"HotelService/application/dto"
+	"HotelService/domain/model"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type AuditSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewAuditSQLiteRepository(db *sql.DB) *AuditSQLiteRepository {
+	return &AuditSQLiteRepository{db: db}
+}
+
+func (r *AuditSQLiteRepository) Save(ctx context.Context, entry *model.AuditEntry) error {
+	if entry == nil {
+		return fmt.Errorf("audit entry cannot be nil")
+	}
+
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+
+	query := `
+		INSERT INTO audit_log (hotel_id, actor_id, action, entity_type, entity_id, changes, created_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+		RETURNING id`
+
+	now := sqliteNow()
+	err = conn(ctx, r.db).QueryRowContext(ctx, query,
+		entry.HotelID,
+		sql.NullInt64{Int64: entry.ActorID, Valid: entry.ActorID != 0},
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		string(changes),
+		now,
+	).Scan(&entry.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to save audit entry: %w", err)
+	}
+
+	entry.CreatedAt = now
+	return nil
+}
+
+func (r *AuditSQLiteRepository) FindPage(ctx context.Context, filter dto.AuditFilter) ([]*model.AuditEntry, string, error) {
+	args := []interface{}{filter.HotelID}
+	conditions := []string{"hotel_id = ?1"}
+
+	if filter.ActorID > 0 {
+		args = append(args, filter.ActorID)
+		conditions = append(conditions, fmt.Sprintf("actor_id = ?%d", len(args)))
+	}
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conditions = append(conditions, fmt.Sprintf("action = ?%d", len(args)))
+	}
+	if filter.EntityType != "" {
+		args = append(args, filter.EntityType)
+		conditions = append(conditions, fmt.Sprintf("entity_type = ?%d", len(args)))
+	}
+	if filter.EntityID > 0 {
+		args = append(args, filter.EntityID)
+		conditions = append(conditions, fmt.Sprintf("entity_id = ?%d", len(args)))
+	}
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at >= ?%d", len(args)))
+	}
+	if !filter.Until.IsZero() {
+		args = append(args, filter.Until.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at < ?%d", len(args)))
+	}
+	if filter.Cursor != "" {
+		values, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		condition, err := auditSort.sqliteAfter(values, &args)
+		if err != nil {
+			return nil, "", err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	args = append(args, filter.Limit+1)
+	query := `
+		SELECT id, hotel_id, actor_id, action, entity_type, entity_id, changes, created_at
+		FROM audit_log
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + auditSort.orderBy() + fmt.Sprintf(`
+		LIMIT ?%d`, len(args))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAuditPage(rows, filter.Limit)
+}
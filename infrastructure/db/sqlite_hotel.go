@@ -0,0 +1,714 @@
+package db
+
+import (
+	"HotelService/application/dto"
+	"HotelService/domain/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type HotelSQLiteRepository struct {
+	db *sql.DB
+}
+
+type RoomSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewHotelSQLiteRepository(db *sql.DB) *HotelSQLiteRepository {
+	return &HotelSQLiteRepository{db: db}
+}
+
+func NewRoomSQLiteRepository(db *sql.DB) *RoomSQLiteRepository {
+	return &RoomSQLiteRepository{db: db}
+}
+
+// HotelSQLiteRepository
+
+func (r *HotelSQLiteRepository) Save(ctx context.Context, hotel *model.Hotel) error {
+	if hotel == nil {
+		return fmt.Errorf("hotel cannot be nil")
+	}
+
+	now := sqliteNow()
+	query := `
+		INSERT INTO hotels (name, address, currency, owner_id, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+		RETURNING id`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		hotel.Name,
+		hotel.Address,
+		hotel.Currency,
+		sql.NullInt64{Int64: hotel.OwnerID, Valid: hotel.OwnerID != 0},
+		now,
+		now,
+	).Scan(&hotel.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to save hotel: %w", err)
+	}
+
+	hotel.CreatedAt = now
+	hotel.UpdatedAt = now
+	hotel.Version = 1
+	return nil
+}
+
+func (r *HotelSQLiteRepository) Update(ctx context.Context, hotel *model.Hotel) error {
+	if hotel == nil {
+		return fmt.Errorf("hotel cannot be nil")
+	}
+	if hotel.ID == 0 {
+		return fmt.Errorf("hotel ID is required for update")
+	}
+
+	query := `
+		UPDATE hotels
+		SET name = ?1, address = ?2, updated_at = ?3, version = version + 1
+		WHERE id = ?4 AND version = ?5 AND deleted_at IS NULL`
+
+	now := sqliteNow()
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		hotel.Name,
+		hotel.Address,
+		now,
+		hotel.ID,
+		hotel.Version,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update hotel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return staleOrMissing(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM hotels WHERE id = ?1 AND deleted_at IS NULL)`, "hotel", hotel.ID, hotel.Version)
+	}
+
+	hotel.UpdatedAt = now
+	hotel.Version++
+	return nil
+}
+
+func (r *HotelSQLiteRepository) FindByID(ctx context.Context, id int64) (*model.Hotel, error) {
+	query := `
+		SELECT id, name, address, currency, owner_id, version, created_at, updated_at, deleted_at
+		FROM hotels
+		WHERE id = ?1 AND deleted_at IS NULL`
+
+	hotel, err := scanHotel(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("hotel with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to find hotel: %w", err)
+	}
+
+	roomsQuery := `
+		SELECT id, hotel_id, number, type, price, capacity, available, created_at, updated_at, version
+		FROM rooms
+		WHERE hotel_id = ?1 AND deleted_at IS NULL
+		ORDER BY number`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, roomsQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rooms: %w", err)
+	}
+	defer rows.Close()
+
+	var rooms []model.Room
+	for rows.Next() {
+		var room model.Room
+		var price string
+		if err := rows.Scan(&room.ID, &room.HotelID, &room.Number, &room.Type, &price, &room.Capacity, &room.Available, &room.CreatedAt, &room.UpdatedAt, &room.Version); err != nil {
+			return nil, fmt.Errorf("failed to scan room: %w", err)
+		}
+		if room.Price, err = parsePrice(price, hotel.Currency); err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, room)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rooms: %w", err)
+	}
+
+	hotel.Rooms = rooms
+	return hotel, nil
+}
+
+func (r *HotelSQLiteRepository) FindAll(ctx context.Context) ([]*model.Hotel, error) {
+	query := `
+		SELECT id, name, address, currency, owner_id, version, created_at, updated_at, deleted_at
+		FROM hotels
+		WHERE deleted_at IS NULL
+		ORDER BY created_at DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find all hotels: %w", err)
+	}
+	defer rows.Close()
+
+	return scanHotels(rows)
+}
+
+// sqliteHotelSorts match hotelSorts, except that created_at cursor values are
+// written in the stored text format so they compare as SQLite compares them
+var sqliteHotelSorts = map[string]struct {
+	keyset
+	values func(hotel *model.Hotel) []string
+}{
+	"created_at": {
+		keyset{columns: []string{"created_at", "id"}},
+		func(hotel *model.Hotel) []string {
+			return []string{hotel.CreatedAt.UTC().Format(sqliteTimeFormat), strconv.FormatInt(hotel.ID, 10)}
+		},
+	},
+	"-created_at": {
+		keyset{columns: []string{"created_at", "id"}, desc: true},
+		func(hotel *model.Hotel) []string {
+			return []string{hotel.CreatedAt.UTC().Format(sqliteTimeFormat), strconv.FormatInt(hotel.ID, 10)}
+		},
+	},
+	"name":  hotelSorts["name"],
+	"-name": hotelSorts["-name"],
+}
+
+// FindPage returns one page of hotels matching the filter plus the cursor of the next page.
+// An empty sort lists the newest hotels first.
+func (r *HotelSQLiteRepository) FindPage(ctx context.Context, filter dto.HotelFilter) ([]*model.Hotel, string, error) {
+	sortKey := filter.Sort
+	if sortKey == "" {
+		sortKey = "-created_at"
+	}
+	sort, ok := sqliteHotelSorts[sortKey]
+	if !ok {
+		return nil, "", model.NewValidationError("unsupported sort %q", filter.Sort)
+	}
+
+	// LIKE is case-insensitive for ASCII in SQLite, standing in for ILIKE
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+
+	if filter.Name != "" {
+		args = append(args, likePattern(filter.Name))
+		conditions = append(conditions, fmt.Sprintf(`name LIKE ?%d ESCAPE '\'`, len(args)))
+	}
+	if filter.Address != "" {
+		args = append(args, likePattern(filter.Address))
+		conditions = append(conditions, fmt.Sprintf(`address LIKE ?%d ESCAPE '\'`, len(args)))
+	}
+	if filter.Cursor != "" {
+		values, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		condition, err := sort.sqliteAfter(values, &args)
+		if err != nil {
+			return nil, "", err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	args = append(args, filter.Limit+1)
+	query := `
+		SELECT id, name, address, currency, owner_id, version, created_at, updated_at, deleted_at
+		FROM hotels
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + sort.orderBy() + fmt.Sprintf(`
+		LIMIT ?%d`, len(args))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find hotels: %w", err)
+	}
+	defer rows.Close()
+
+	hotels, err := scanHotels(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(hotels) > filter.Limit {
+		hotels = hotels[:filter.Limit]
+		nextCursor = encodeCursor(sort.values(hotels[len(hotels)-1]))
+	}
+
+	return hotels, nextCursor, nil
+}
+
+// Delete removes the hotel for good, cascading to its rooms, rate plans and
+// staff, and deletes the reservations of its rooms, which fk_room would
+// otherwise restrict. Run it in a unit of work so nothing is left half deleted.
+// The API soft-deletes with SoftDelete instead.
+func (r *HotelSQLiteRepository) Delete(ctx context.Context, id int64) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM reservations WHERE room_id IN (SELECT id FROM rooms WHERE hotel_id = ?1)`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete reservations: %w", err)
+	}
+
+	query := `DELETE FROM hotels WHERE id = ?1`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete hotel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.NewNotFoundError("hotel with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// SoftDelete hides the hotel and its rooms from every query. Run it in a unit
+// of work so the hotel and its rooms are hidden together.
+func (r *HotelSQLiteRepository) SoftDelete(ctx context.Context, id, version int64) error {
+	now := sqliteNow()
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE hotels SET deleted_at = ?1, version = version + 1
+		WHERE id = ?2 AND deleted_at IS NULL AND (?3 = 0 OR version = ?3)`, now, id, version)
+	if err != nil {
+		return fmt.Errorf("failed to delete hotel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return staleOrMissing(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM hotels WHERE id = ?1 AND deleted_at IS NULL)`, "hotel", id, version)
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, `UPDATE rooms SET deleted_at = ?1 WHERE hotel_id = ?2 AND deleted_at IS NULL`, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete rooms: %w", err)
+	}
+
+	return nil
+}
+
+// FindDeletedByID loads a soft-deleted hotel without its rooms
+func (r *HotelSQLiteRepository) FindDeletedByID(ctx context.Context, id int64) (*model.Hotel, error) {
+	query := `
+		SELECT id, name, address, currency, owner_id, version, created_at, updated_at, deleted_at
+		FROM hotels
+		WHERE id = ?1 AND deleted_at IS NOT NULL`
+
+	hotel, err := scanHotel(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("deleted hotel with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to find deleted hotel: %w", err)
+	}
+
+	return hotel, nil
+}
+
+// Restore brings back a soft-deleted hotel together with the rooms deleted
+// with it, which share its deleted_at; rooms deleted before the hotel stay
+// deleted. Run it in a unit of work like SoftDelete.
+func (r *HotelSQLiteRepository) Restore(ctx context.Context, id int64) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE rooms SET deleted_at = NULL
+		WHERE hotel_id = ?1 AND deleted_at = (SELECT deleted_at FROM hotels WHERE id = ?1)`, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore rooms: %w", err)
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE hotels SET deleted_at = NULL, version = version + 1 WHERE id = ?1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore hotel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.NewNotFoundError("deleted hotel with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// BumpVersion marks the hotel changed after a change of its rooms
+func (r *HotelSQLiteRepository) BumpVersion(ctx context.Context, id int64) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE hotels SET version = version + 1, updated_at = ?1 WHERE id = ?2 AND deleted_at IS NULL`, sqliteNow(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update hotel version: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.NewNotFoundError("hotel with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// sqlitePurgeableHotels selects the hotels soft-deleted before ?1 none of whose
+// rooms has a reservation in status ?2 ending after ?3
+const sqlitePurgeableHotels = `
+	SELECT h.id
+	FROM hotels h
+	WHERE h.deleted_at < ?1
+	  AND NOT EXISTS (
+		SELECT 1
+		FROM reservations res
+		JOIN rooms r ON r.id = res.room_id
+		WHERE r.hotel_id = h.id AND res.status = ?2 AND res.check_out > ?3
+	  )`
+
+// PurgeDeleted removes hotels soft-deleted before the cutoff for good and
+// returns them. Their reservations are all cancelled or past, and are removed
+// first since fk_room restricts taking them along; run it in a unit of work.
+func (r *HotelSQLiteRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]*model.Hotel, error) {
+	args := []interface{}{before.UTC(), model.ReservationStatusConfirmed, sqliteDate(today())}
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		DELETE FROM reservations
+		WHERE room_id IN (SELECT id FROM rooms WHERE hotel_id IN (`+sqlitePurgeableHotels+`))`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge reservations: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		DELETE FROM hotels
+		WHERE id IN (`+sqlitePurgeableHotels+`)
+		RETURNING id, name, address, currency, owner_id, version, created_at, updated_at, deleted_at`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge deleted hotels: %w", err)
+	}
+	defer rows.Close()
+
+	hotels, err := scanHotels(rows)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(hotels, func(i, j int) bool { return hotels[i].ID < hotels[j].ID })
+
+	return hotels, nil
+}
+
+// RoomSQLiteRepository
+
+func (r *RoomSQLiteRepository) Save(ctx context.Context, room *model.Room) error {
+	if room == nil {
+		return fmt.Errorf("room cannot be nil")
+	}
+
+	query := `
+		INSERT INTO rooms (hotel_id, number, type, price, capacity, available, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
+		RETURNING id`
+
+	now := sqliteNow()
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		room.HotelID,
+		room.Number,
+		room.Type,
+		room.Price.Decimal(),
+		room.Capacity,
+		room.Available,
+		now,
+		now,
+	).Scan(&room.ID)
+
+	if err != nil {
+		if isSQLiteViolation(err, sqliteConstraintUnique, "rooms.hotel_id, rooms.number") {
+			return model.NewConflictError("room %s already exists in hotel %d", room.Number, room.HotelID)
+		}
+		if isSQLiteViolation(err, sqliteConstraintForeignKey, "") {
+			return model.NewNotFoundError("hotel with ID %d not found", room.HotelID)
+		}
+		return fmt.Errorf("failed to save room: %w", err)
+	}
+
+	room.CreatedAt = now
+	room.UpdatedAt = now
+	room.Version = 1
+	return nil
+}
+
+func (r *RoomSQLiteRepository) Update(ctx context.Context, room *model.Room) error {
+	if room == nil {
+		return fmt.Errorf("room cannot be nil")
+	}
+	if room.ID == 0 {
+		return fmt.Errorf("room ID is required for update")
+	}
+
+	query := `
+		UPDATE rooms
+		SET hotel_id = ?1, number = ?2, type = ?3, price = ?4, capacity = ?5, available = ?6, updated_at = ?7, version = version + 1
+		WHERE id = ?8 AND version = ?9 AND deleted_at IS NULL`
+
+	now := sqliteNow()
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		room.HotelID,
+		room.Number,
+		room.Type,
+		room.Price.Decimal(),
+		room.Capacity,
+		room.Available,
+		now,
+		room.ID,
+		room.Version,
+	)
+
+	if err != nil {
+		if isSQLiteViolation(err, sqliteConstraintUnique, "rooms.hotel_id, rooms.number") {
+			return model.NewConflictError("room %s already exists in hotel %d", room.Number, room.HotelID)
+		}
+		return fmt.Errorf("failed to update room: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return staleOrMissing(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM rooms WHERE id = ?1 AND deleted_at IS NULL)`, "room", room.ID, room.Version)
+	}
+
+	room.UpdatedAt = now
+	room.Version++
+	return nil
+}
+
+func (r *RoomSQLiteRepository) FindByID(ctx context.Context, id int64) (*model.Room, error) {
+	query := `
+		SELECT r.id, r.hotel_id, r.number, r.type, r.price, h.currency, r.capacity, r.available, r.created_at, r.updated_at, r.version
+		FROM rooms r
+		JOIN hotels h ON h.id = r.hotel_id
+		WHERE r.id = ?1 AND r.deleted_at IS NULL`
+
+	room, err := scanRoom(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("room with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to find room: %w", err)
+	}
+
+	return room, nil
+}
+
+func (r *RoomSQLiteRepository) FindAll(ctx context.Context) ([]*model.Room, error) {
+	query := `
+		SELECT r.id, r.hotel_id, r.number, r.type, r.price, h.currency, r.capacity, r.available, r.created_at, r.updated_at, r.version
+		FROM rooms r
+		JOIN hotels h ON h.id = r.hotel_id
+		WHERE r.deleted_at IS NULL
+		ORDER BY r.hotel_id, r.number`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find all rooms: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRooms(rows)
+}
+
+func (r *RoomSQLiteRepository) FindByHotelID(ctx context.Context, hotelID int64) ([]*model.Room, error) {
+	query := `
+		SELECT r.id, r.hotel_id, r.number, r.type, r.price, h.currency, r.capacity, r.available, r.created_at, r.updated_at, r.version
+		FROM rooms r
+		JOIN hotels h ON h.id = r.hotel_id
+		WHERE r.hotel_id = ?1 AND r.deleted_at IS NULL
+		ORDER BY r.number`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rooms by hotel ID: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRooms(rows)
+}
+
+func (r *RoomSQLiteRepository) FindAllAvailable(ctx context.Context) ([]*model.Room, error) {
+	query := `
+		SELECT r.id, r.hotel_id, r.number, r.type, r.price, h.currency, r.capacity, r.available, r.created_at, r.updated_at, r.version
+		FROM rooms r
+		JOIN hotels h ON h.id = r.hotel_id
+		WHERE r.available = TRUE AND r.deleted_at IS NULL
+		ORDER BY r.hotel_id, r.number`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find available rooms: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRooms(rows)
+}
+
+// FindAvailableForStay returns one page of open rooms that match the search and have no
+// confirmed reservation overlapping the requested stay, plus the cursor of the next page
+func (r *RoomSQLiteRepository) FindAvailableForStay(ctx context.Context, search dto.StaySearch) ([]*model.Room, string, error) {
+	sort, ok := roomSorts[search.Sort]
+	if !ok {
+		return nil, "", model.NewValidationError("unsupported sort %q", search.Sort)
+	}
+
+	conditions := []string{"r.available = TRUE", "r.deleted_at IS NULL"}
+	var args []interface{}
+
+	if search.HotelID > 0 {
+		args = append(args, search.HotelID)
+		conditions = append(conditions, fmt.Sprintf("r.hotel_id = ?%d", len(args)))
+	}
+	if search.Guests > 0 {
+		args = append(args, search.Guests)
+		conditions = append(conditions, fmt.Sprintf("r.capacity >= ?%d", len(args)))
+	}
+	if search.Type != "" {
+		args = append(args, search.Type)
+		conditions = append(conditions, fmt.Sprintf("r.type = ?%d", len(args)))
+	}
+	if search.MinPrice != nil {
+		args = append(args, search.MinPrice.Currency, search.MinPrice.Decimal())
+		conditions = append(conditions, fmt.Sprintf("h.currency = ?%d AND r.price >= ?%d", len(args)-1, len(args)))
+	}
+	if search.MaxPrice != nil {
+		args = append(args, search.MaxPrice.Currency, search.MaxPrice.Decimal())
+		conditions = append(conditions, fmt.Sprintf("h.currency = ?%d AND r.price <= ?%d", len(args)-1, len(args)))
+	}
+	if !search.CheckIn.IsZero() && !search.CheckOut.IsZero() {
+		args = append(args, model.ReservationStatusConfirmed, sqliteDate(search.CheckIn), sqliteDate(search.CheckOut))
+		conditions = append(conditions, fmt.Sprintf(`NOT EXISTS (
+			SELECT 1
+			FROM reservations res
+			WHERE res.room_id = r.id
+			  AND res.status = ?%d
+			  AND res.check_in < ?%d
+			  AND res.check_out > ?%d
+		)`, len(args)-2, len(args), len(args)-1))
+	}
+	if search.Cursor != "" {
+		values, err := decodeCursor(search.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		condition, err := sort.sqliteAfter(values, &args)
+		if err != nil {
+			return nil, "", err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	args = append(args, search.Limit+1)
+	query := `
+		SELECT r.id, r.hotel_id, r.number, r.type, r.price, h.currency, r.capacity, r.available, r.created_at, r.updated_at, r.version
+		FROM rooms r
+		JOIN hotels h ON h.id = r.hotel_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + sort.orderBy() + fmt.Sprintf(`
+		LIMIT ?%d`, len(args))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find rooms for stay: %w", err)
+	}
+	defer rows.Close()
+
+	rooms, err := scanRooms(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(rooms) > search.Limit {
+		rooms = rooms[:search.Limit]
+		nextCursor = encodeCursor(sort.values(rooms[len(rooms)-1]))
+	}
+
+	return rooms, nextCursor, nil
+}
+
+// Delete soft-deletes the room, keeping its reservations on record. It fails
+// with ErrConflict while a confirmed stay has yet to end.
+func (r *RoomSQLiteRepository) Delete(ctx context.Context, id, version int64) error {
+	query := `
+		UPDATE rooms SET deleted_at = ?3, version = version + 1
+		WHERE id = ?1 AND deleted_at IS NULL AND (?2 = 0 OR version = ?2)
+		  AND NOT EXISTS (
+			SELECT 1 FROM reservations
+			WHERE room_id = ?1 AND status = ?4 AND check_out > ?5
+		  )`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, version, sqliteNow(), model.ReservationStatusConfirmed, sqliteDate(today()))
+	if err != nil {
+		return fmt.Errorf("failed to delete room: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		// A current room at the expected version was kept by an upcoming stay
+		current, err := exists(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM rooms WHERE id = ?1 AND deleted_at IS NULL AND (?2 = 0 OR version = ?2))`, id, version)
+		if err != nil {
+			return err
+		}
+		if current {
+			return model.NewConflictError("room %d has upcoming reservations", id)
+		}
+		return staleOrMissing(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM rooms WHERE id = ?1 AND deleted_at IS NULL)`, "room", id, version)
+	}
+
+	return nil
+}
+
+func (r *RoomSQLiteRepository) UpdateAvailability(ctx context.Context, id int64, available bool, version int64) error {
+	query := `
+		UPDATE rooms
+		SET available = ?1, updated_at = ?2, version = version + 1
+		WHERE id = ?3 AND version = ?4 AND deleted_at IS NULL`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, available, sqliteNow(), id, version)
+	if err != nil {
+		return fmt.Errorf("failed to update room availability: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return staleOrMissing(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM rooms WHERE id = ?1 AND deleted_at IS NULL)`, "room", id, version)
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package db
+
+import (
+	"HotelService/domain/model"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type HotelierSQLiteRepository struct {
+	db *sql.DB
+}
+
+type StaffSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewHotelierSQLiteRepository(db *sql.DB) *HotelierSQLiteRepository {
+	return &HotelierSQLiteRepository{db: db}
+}
+
+func NewStaffSQLiteRepository(db *sql.DB) *StaffSQLiteRepository {
+	return &StaffSQLiteRepository{db: db}
+}
+
+// HotelierSQLiteRepository
+
+func (r *HotelierSQLiteRepository) Save(ctx context.Context, hotelier *model.Hotelier) error {
+	if hotelier == nil {
+		return fmt.Errorf("hotelier cannot be nil")
+	}
+
+	query := `
+		INSERT INTO hoteliers (email, name, password_hash, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5)
+		RETURNING id`
+
+	now := sqliteNow()
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		hotelier.Email,
+		hotelier.Name,
+		hotelier.PasswordHash,
+		now,
+		now,
+	).Scan(&hotelier.ID)
+
+	if err != nil {
+		if isSQLiteViolation(err, sqliteConstraintUnique, "hoteliers.email") {
+			return model.NewConflictError("an account with email %s already exists", hotelier.Email)
+		}
+		return fmt.Errorf("failed to save hotelier: %w", err)
+	}
+
+	hotelier.CreatedAt = now
+	hotelier.UpdatedAt = now
+	return nil
+}
+
+func (r *HotelierSQLiteRepository) FindByID(ctx context.Context, id int64) (*model.Hotelier, error) {
+	query := `
+		SELECT id, email, name, password_hash, created_at, updated_at
+		FROM hoteliers
+		WHERE id = ?1`
+
+	hotelier, err := scanHotelier(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("hotelier with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to find hotelier: %w", err)
+	}
+
+	return hotelier, nil
+}
+
+func (r *HotelierSQLiteRepository) FindByEmail(ctx context.Context, email string) (*model.Hotelier, error) {
+	query := `
+		SELECT id, email, name, password_hash, created_at, updated_at
+		FROM hoteliers
+		WHERE email = ?1`
+
+	hotelier, err := scanHotelier(conn(ctx, r.db).QueryRowContext(ctx, query, email))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("hotelier with email %s not found", email)
+		}
+		return nil, fmt.Errorf("failed to find hotelier: %w", err)
+	}
+
+	return hotelier, nil
+}
+
+// StaffSQLiteRepository
+
+func (r *StaffSQLiteRepository) Save(ctx context.Context, member *model.StaffMember) error {
+	if member == nil {
+		return fmt.Errorf("staff member cannot be nil")
+	}
+
+	query := `
+		INSERT INTO hotel_staff (hotel_id, hotelier_id, role, created_at)
+		VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (hotel_id, hotelier_id) DO UPDATE SET role = excluded.role
+		RETURNING created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		member.HotelID,
+		member.HotelierID,
+		member.Role,
+		member.CreatedAt.UTC(),
+	).Scan(&member.CreatedAt)
+
+	if err != nil {
+		if isSQLiteViolation(err, sqliteConstraintForeignKey, "") {
+			return r.missingReference(ctx, member)
+		}
+		if isSQLiteViolation(err, sqliteConstraintCheck, "check_staff_role") {
+			return model.NewValidationError("unknown role %s", member.Role)
+		}
+		return fmt.Errorf("failed to save staff member: %w", err)
+	}
+
+	return nil
+}
+
+// missingReference reports which of the member's hotel, account and role does not exist
+func (r *StaffSQLiteRepository) missingReference(ctx context.Context, member *model.StaffMember) error {
+	found, err := exists(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM hotels WHERE id = ?1)`, member.HotelID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return model.NewNotFoundError("hotel with ID %d not found", member.HotelID)
+	}
+
+	found, err = exists(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM hoteliers WHERE id = ?1)`, member.HotelierID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return model.NewNotFoundError("hotelier with ID %d not found", member.HotelierID)
+	}
+
+	return model.NewValidationError("unknown role %s", member.Role)
+}
+
+func (r *StaffSQLiteRepository) Delete(ctx context.Context, hotelID, hotelierID int64) error {
+	query := `DELETE FROM hotel_staff WHERE hotel_id = ?1 AND hotelier_id = ?2`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, hotelID, hotelierID)
+	if err != nil {
+		return fmt.Errorf("failed to delete staff member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.NewNotFoundError("hotelier %d is not on the staff of hotel %d", hotelierID, hotelID)
+	}
+
+	return nil
+}
+
+func (r *StaffSQLiteRepository) FindByHotelID(ctx context.Context, hotelID int64) ([]*model.StaffMember, error) {
+	query := `
+		SELECT s.hotel_id, s.hotelier_id, h.email, h.name, s.role, s.created_at
+		FROM hotel_staff s
+		JOIN hoteliers h ON h.id = s.hotelier_id
+		WHERE s.hotel_id = ?1
+		ORDER BY s.role, h.name, s.hotelier_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query staff: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*model.StaffMember
+	for rows.Next() {
+		member := &model.StaffMember{}
+		err := rows.Scan(
+			&member.HotelID,
+			&member.HotelierID,
+			&member.Email,
+			&member.Name,
+			&member.Role,
+			&member.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan staff member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating staff: %w", err)
+	}
+
+	return members, nil
+}
+
+func (r *StaffSQLiteRepository) FindRole(ctx context.Context, hotelID, hotelierID int64) (model.Role, error) {
+	query := `SELECT role FROM hotel_staff WHERE hotel_id = ?1 AND hotelier_id = ?2`
+
+	var role model.Role
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, hotelID, hotelierID).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", model.NewNotFoundError("hotelier %d is not on the staff of hotel %d", hotelierID, hotelID)
+		}
+		return "", fmt.Errorf("failed to find staff role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (r *StaffSQLiteRepository) RoleHasPermission(ctx context.Context, role model.Role, permission model.Permission) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM role_permissions WHERE role = ?1 AND permission = ?2)`
+
+	var allowed bool
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, role, permission).Scan(&allowed); err != nil {
+		return false, fmt.Errorf("failed to check role permission: %w", err)
+	}
+
+	return allowed, nil
+}
@@ -0,0 +1,126 @@
+package db
+
+import (
+	"HotelService/domain/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type IdempotencySQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencySQLiteRepository(db *sql.DB) *IdempotencySQLiteRepository {
+	return &IdempotencySQLiteRepository{db: db}
+}
+
+func (r *IdempotencySQLiteRepository) Save(ctx context.Context, request *model.IdempotentRequest) error {
+	if request == nil {
+		return fmt.Errorf("idempotent request cannot be nil")
+	}
+
+	query := `
+		INSERT INTO idempotency_keys (hotelier_id, idempotency_key, request_hash, created_at)
+		VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (hotelier_id, idempotency_key) DO NOTHING`
+
+	now := sqliteNow()
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, request.HotelierID, request.Key, request.RequestHash, now)
+	if err != nil {
+		if isSQLiteViolation(err, sqliteConstraintForeignKey, "") {
+			return model.NewNotFoundError("hotelier with ID %d not found", request.HotelierID)
+		}
+		return fmt.Errorf("failed to save idempotency key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.NewConflictError("idempotency key %q has already been used", request.Key)
+	}
+
+	request.CreatedAt = now
+	return nil
+}
+
+func (r *IdempotencySQLiteRepository) FindByKey(ctx context.Context, hotelierID int64, key string) (*model.IdempotentRequest, error) {
+	query := `
+		SELECT hotelier_id, idempotency_key, request_hash, status_code, content_type, body, created_at
+		FROM idempotency_keys
+		WHERE hotelier_id = ?1 AND idempotency_key = ?2`
+
+	request, err := scanIdempotentRequest(conn(ctx, r.db).QueryRowContext(ctx, query, hotelierID, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("idempotency key %q not found", key)
+		}
+		return nil, fmt.Errorf("failed to find idempotency key: %w", err)
+	}
+
+	return request, nil
+}
+
+// Complete stores the response to a saved request
+func (r *IdempotencySQLiteRepository) Complete(ctx context.Context, request *model.IdempotentRequest) error {
+	if request == nil {
+		return fmt.Errorf("idempotent request cannot be nil")
+	}
+
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = ?1, content_type = ?2, body = ?3
+		WHERE hotelier_id = ?4 AND idempotency_key = ?5`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		request.StatusCode,
+		request.ContentType,
+		request.Body,
+		request.HotelierID,
+		request.Key,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.NewNotFoundError("idempotency key %q not found", request.Key)
+	}
+
+	return nil
+}
+
+func (r *IdempotencySQLiteRepository) Delete(ctx context.Context, hotelierID int64, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE hotelier_id = ?1 AND idempotency_key = ?2`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, hotelierID, key); err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeBefore removes the keys saved before the cutoff and returns how many
+// were removed
+func (r *IdempotencySQLiteRepository) PurgeBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at < ?1`, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,79 @@
+package db_test
+
+import (
+	"HotelService/application/service"
+	"HotelService/domain/model"
+	"HotelService/infrastructure/db"
+	"HotelService/infrastructure/repotest"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSQLiteRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) service.Repositories {
+		database, _ := newSQLiteDB(t)
+		return db.NewSQLiteRepositories(database)
+	})
+}
+
+func TestSQLiteMigrationsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	database, migrator := newSQLiteDB(t)
+
+	_, latest, err := migrator.SchemaVersion(ctx)
+	if err != nil {
+		t.Fatalf("SchemaVersion: %v", err)
+	}
+
+	reverted, err := migrator.Down(ctx, int(latest))
+	if err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if int64(len(reverted)) != latest {
+		t.Fatalf("Down reverted %d migrations, want %d", len(reverted), latest)
+	}
+	if applied, _, err := migrator.SchemaVersion(ctx); err != nil || applied != 0 {
+		t.Fatalf("SchemaVersion after Down = %d, %v, want 0", applied, err)
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up after Down: %v", err)
+	}
+	if int64(len(applied)) != latest {
+		t.Fatalf("Up applied %d migrations, want %d", len(applied), latest)
+	}
+
+	// The schema rebuilt by the up migrations must still hold data
+	repos := db.NewSQLiteRepositories(database)
+	hotel := &model.Hotel{Name: "Round Trip Hotel", Address: "1 Main St", Currency: "USD"}
+	if err := repos.Hotels.Save(ctx, hotel); err != nil {
+		t.Fatalf("Save after round trip: %v", err)
+	}
+}
+
+// newSQLiteDB opens a migrated database in a file that is removed after the test
+func newSQLiteDB(t *testing.T) (*sql.DB, *db.Migrator) {
+	t.Helper()
+	database, err := db.NewSQLiteDB(db.SQLiteConfig{
+		Path:        filepath.Join(t.TempDir(), "hotel.db"),
+		BusyTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewSQLiteDB: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	migrator, err := db.NewSQLiteMigrator(database)
+	if err != nil {
+		t.Fatalf("NewSQLiteMigrator: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	return database, migrator
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"HotelService/domain/model"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type StaffPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewStaffRepository(db *sql.DB) *StaffPostgresRepository {
+	return &StaffPostgresRepository{db: db}
+}
+
+func (r *StaffPostgresRepository) Save(ctx context.Context, member *model.StaffMember) error {
+	if member == nil {
+		return fmt.Errorf("staff member cannot be nil")
+	}
+
+	query := `
+		INSERT INTO hotel_staff (hotel_id, hotelier_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (hotel_id, hotelier_id) DO UPDATE SET role = EXCLUDED.role
+		RETURNING created_at`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		member.HotelID,
+		member.HotelierID,
+		member.Role,
+		member.CreatedAt,
+	).Scan(&member.CreatedAt)
+
+	if err != nil {
+		if isConstraintViolation(err, pgForeignKeyViolation, "fk_staff_hotel") {
+			return model.NewNotFoundError("hotel with ID %d not found", member.HotelID)
+		}
+		if isConstraintViolation(err, pgForeignKeyViolation, "fk_staff_hotelier") {
+			return model.NewNotFoundError("hotelier with ID %d not found", member.HotelierID)
+		}
+		if isConstraintViolation(err, pgForeignKeyViolation, "fk_staff_role") {
+			return model.NewValidationError("unknown role %s", member.Role)
+		}
+		return fmt.Errorf("failed to save staff member: %w", err)
+	}
+
+	return nil
+}
+
+func (r *StaffPostgresRepository) Delete(ctx context.Context, hotelID, hotelierID int64) error {
+	query := `DELETE FROM hotel_staff WHERE hotel_id = $1 AND hotelier_id = $2`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, hotelID, hotelierID)
+	if err != nil {
+		return fmt.Errorf("failed to delete staff member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return model.NewNotFoundError("hotelier %d is not on the staff of hotel %d", hotelierID, hotelID)
+	}
+
+	return nil
+}
+
+func (r *StaffPostgresRepository) FindByHotelID(ctx context.Context, hotelID int64) ([]*model.StaffMember, error) {
+	query := `
+		SELECT s.hotel_id, s.hotelier_id, h.email, h.name, s.role, s.created_at
+		FROM hotel_staff s
+		JOIN hoteliers h ON h.id = s.hotelier_id
+		WHERE s.hotel_id = $1
+		ORDER BY s.role, h.name, s.hotelier_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, hotelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query staff: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*model.StaffMember
+	for rows.Next() {
+		member := &model.StaffMember{}
+		err := rows.Scan(
+			&member.HotelID,
+			&member.HotelierID,
+			&member.Email,
+			&member.Name,
+			&member.Role,
+			&member.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan staff member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating staff: %w", err)
+	}
+
+	return members, nil
+}
+
+func (r *StaffPostgresRepository) FindRole(ctx context.Context, hotelID, hotelierID int64) (model.Role, error) {
+	query := `SELECT role FROM hotel_staff WHERE hotel_id = $1 AND hotelier_id = $2`
+
+	var role model.Role
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, hotelID, hotelierID).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", model.NewNotFoundError("hotelier %d is not on the staff of hotel %d", hotelierID, hotelID)
+		}
+		return "", fmt.Errorf("failed to find staff role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (r *StaffPostgresRepository) RoleHasPermission(ctx context.Context, role model.Role, permission model.Permission) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM role_permissions WHERE role = $1 AND permission = $2)`
+
+	var allowed bool
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, role, permission).Scan(&allowed); err != nil {
+		return false, fmt.Errorf("failed to check role permission: %w", err)
+	}
+
+	return allowed, nil
+}
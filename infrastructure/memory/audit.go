@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"HotelService/application/dto"
+	"HotelService/domain/model"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type AuditRepository struct {
+	store *Store
+}
+
+func NewAuditRepository(store *Store) *AuditRepository {
+	return &AuditRepository{store: store}
+}
+
+func (r *AuditRepository) Save(ctx context.Context, entry *model.AuditEntry) error {
+	if entry == nil {
+		return fmt.Errorf("audit entry cannot be nil")
+	}
+
+	return r.store.write(ctx, func(t *tables) error {
+		// Entries outlive their hotel, so only the actor is checked
+		if _, ok := t.hoteliers[entry.ActorID]; entry.ActorID != 0 && !ok {
+			return fmt.Errorf("failed to save audit entry: hotelier %d does not exist", entry.ActorID)
+		}
+
+		t.seq.auditLog++
+		entry.ID = t.seq.auditLog
+		entry.CreatedAt = time.Now()
+
+		t.auditLog[entry.ID] = *entry
+		return nil
+	})
+}
+
+// auditSort lists entries newest first; ids grow with time
+var auditSort = keyset[*model.AuditEntry]{
+	columns: []column{intColumn},
+	desc:    true,
+	values: func(entry *model.AuditEntry) []string {
+		return []string{strconv.FormatInt(entry.ID, 10)}
+	},
+}
+
+func (r *AuditRepository) FindPage(ctx context.Context, filter dto.AuditFilter) ([]*model.AuditEntry, string, error) {
+	var entries []*model.AuditEntry
+	r.store.read(func(t *tables) error {
+		for _, row := range t.auditLog {
+			switch {
+			case row.HotelID != filter.HotelID,
+				filter.ActorID > 0 && row.ActorID != filter.ActorID,
+				filter.Action != "" && row.Action != filter.Action,
+				filter.EntityType != "" && row.EntityType != filter.EntityType,
+				filter.EntityID > 0 && row.EntityID != filter.EntityID,
+				!filter.Since.IsZero() && row.CreatedAt.Before(filter.Since),
+				!filter.Until.IsZero() && !row.CreatedAt.Before(filter.Until):
+				continue
+			}
+
+			entry := row
+			entries = append(entries, &entry)
+		}
+		return nil
+	})
+
+	return auditSort.page(entries, filter.Cursor, filter.Limit)
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"HotelService/domain/model"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// column compares two cursor values of one sort column
+type column func(a, b string) int
+
+func stringColumn(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+func intColumn(a, b string) int {
+	x, _ := strconv.ParseInt(a, 10, 64)
+	y, _ := strconv.ParseInt(b, 10, 64)
+	return compareOrdered(x, y)
+}
+
+func decimalColumn(a, b string) int {
+	x, _ := strconv.ParseFloat(a, 64)
+	y, _ := strconv.ParseFloat(b, 64)
+	return compareOrdered(x, y)
+}
+
+func timeColumn(a, b string) int {
+	x, _ := time.Parse(time.RFC3339Nano, a)
+	y, _ := time.Parse(time.RFC3339Nano, b)
+	return x.Compare(y)
+}
+
+func compareOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// keyset orders rows by the values of its columns, the last of which must be
+// unique. It is the in-memory counterpart of the Postgres keyset pagination.
+type keyset[T any] struct {
+	columns []column
+	desc    bool
+	values  func(row T) []string
+}
+
+// compare orders two rows by their sort values
+func (k keyset[T]) compare(a, b []string) int {
+	for i, col := range k.columns {
+		if c := col(a[i], b[i]); c != 0 {
+			if k.desc {
+				return -c
+			}
+			return c
+		}
+	}
+	return 0
+}
+
+// page sorts rows, drops those up to the cursor and returns at most limit of
+// them together with the cursor of the next page
+func (k keyset[T]) page(rows []T, cursor string, limit int) ([]T, string, error) {
+	keys := make([][]string, len(rows))
+	order := make([]int, len(rows))
+	for i, row := range rows {
+		keys[i] = k.values(row)
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return k.compare(keys[order[a]], keys[order[b]]) < 0
+	})
+
+	var after []string
+	if cursor != "" {
+		values, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(values) != len(k.columns) {
+			return nil, "", model.NewValidationError("cursor does not match the requested sort")
+		}
+		after = values
+	}
+
+	var result []T
+	for _, i := range order {
+		if after != nil && k.compare(keys[i], after) <= 0 {
+			continue
+		}
+		if len(result) == limit {
+			return result, encodeCursor(k.values(result[len(result)-1])), nil
+		}
+		result = append(result, rows[i])
+	}
+
+	return result, "", nil
+}
+
+func encodeCursor(values []string) string {
+	data, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) ([]string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, model.NewValidationError("invalid cursor")
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, model.NewValidationError("invalid cursor")
+	}
+
+	return values, nil
+}
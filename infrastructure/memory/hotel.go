@@ -0,0 +1,519 @@
+package memory
+
+import (
+	"HotelService/application/dto"
+	"HotelService/application/service"
+	"HotelService/domain/model"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type HotelRepository struct {
+	store *Store
+}
+
+type RoomRepository struct {
+	store *Store
+}
+
+func NewHotelRepository(store *Store) *HotelRepository {
+	return &HotelRepository{store: store}
+}
+
+func NewRoomRepository(store *Store) *RoomRepository {
+	return &RoomRepository{store: store}
+}
+
+// HotelRepository
+
+func (r *HotelRepository) Save(ctx context.Context, hotel *model.Hotel) error {
+	if hotel == nil {
+		return fmt.Errorf("hotel cannot be nil")
+	}
+
+	return r.store.write(ctx, func(t *tables) error {
+		if hotel.OwnerID != 0 {
+			if _, ok := t.hoteliers[hotel.OwnerID]; !ok {
+				return fmt.Errorf("failed to save hotel: owner %d does not exist", hotel.OwnerID)
+			}
+		}
+
+		now := time.Now()
+		t.seq.hotels++
+		hotel.ID = t.seq.hotels
+		hotel.CreatedAt = now
+		hotel.UpdatedAt = now
+		hotel.Version = 1
+
+		row := *hotel
+		row.Rooms = nil
+		t.hotels[row.ID] = row
+		return nil
+	})
+}
+
+func (r *HotelRepository) Update(ctx context.Context, hotel *model.Hotel) error {
+	if hotel == nil {
+		return fmt.Errorf("hotel cannot be nil")
+	}
+	if hotel.ID == 0 {
+		return fmt.Errorf("hotel ID is required for update")
+	}
+
+	return r.store.write(ctx, func(t *tables) error {
+		row, ok := t.hotels[hotel.ID]
+		if !ok || row.DeletedAt != nil {
+			return model.NewNotFoundError("hotel with ID %d not found", hotel.ID)
+		}
+		if row.Version != hotel.Version {
+			return model.NewPreconditionFailedError("hotel %d has changed since version %d", hotel.ID, hotel.Version)
+		}
+
+		row.Name = hotel.Name
+		row.Address = hotel.Address
+		row.UpdatedAt = time.Now()
+		row.Version++
+		t.hotels[row.ID] = row
+
+		hotel.UpdatedAt = row.UpdatedAt
+		hotel.Version = row.Version
+		return nil
+	})
+}
+
+func (r *HotelRepository) FindByID(ctx context.Context, id int64) (*model.Hotel, error) {
+	var hotel model.Hotel
+	err := r.store.read(func(t *tables) error {
+		var ok bool
+		if hotel, ok = t.hotels[id]; !ok || hotel.DeletedAt != nil {
+			return model.NewNotFoundError("hotel with ID %d not found", id)
+		}
+
+		for _, room := range t.rooms {
+			if room.HotelID == id && room.DeletedAt == nil {
+				hotel.Rooms = append(hotel.Rooms, room)
+			}
+		}
+		sort.Slice(hotel.Rooms, func(i, j int) bool {
+			return hotel.Rooms[i].Number < hotel.Rooms[j].Number
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &hotel, nil
+}
+
+func (r *HotelRepository) FindAll(ctx context.Context) ([]*model.Hotel, error) {
+	var hotels []*model.Hotel
+	r.store.read(func(t *tables) error {
+		for _, row := range t.hotels {
+			if row.DeletedAt != nil {
+				continue
+			}
+			hotel := row
+			hotels = append(hotels, &hotel)
+		}
+		return nil
+	})
+
+	sort.Slice(hotels, func(i, j int) bool {
+		return hotels[i].CreatedAt.After(hotels[j].CreatedAt)
+	})
+	return hotels, nil
+}
+
+func hotelValues(byName bool) func(hotel *model.Hotel) []string {
+	return func(hotel *model.Hotel) []string {
+		first := hotel.CreatedAt.Format(time.RFC3339Nano)
+		if byName {
+			first = hotel.Name
+		}
+		return []string{first, strconv.FormatInt(hotel.ID, 10)}
+	}
+}
+
+var hotelSorts = map[string]keyset[*model.Hotel]{
+	"created_at":  {columns: []column{timeColumn, intColumn}, values: hotelValues(false)},
+	"-created_at": {columns: []column{timeColumn, intColumn}, desc: true, values: hotelValues(false)},
+	"name":        {columns: []column{stringColumn, intColumn}, values: hotelValues(true)},
+	"-name":       {columns: []column{stringColumn, intColumn}, desc: true, values: hotelValues(true)},
+}
+
+// FindPage returns one page of hotels matching the filter plus the cursor of the next page.
+// An empty sort lists the newest hotels first.
+func (r *HotelRepository) FindPage(ctx context.Context, filter dto.HotelFilter) ([]*model.Hotel, string, error) {
+	sortKey := filter.Sort
+	if sortKey == "" {
+		sortKey = "-created_at"
+	}
+	sort, ok := hotelSorts[sortKey]
+	if !ok {
+		return nil, "", model.NewValidationError("unsupported sort %q", filter.Sort)
+	}
+
+	var hotels []*model.Hotel
+	r.store.read(func(t *tables) error {
+		for _, row := range t.hotels {
+			if row.DeletedAt != nil || !containsFold(row.Name, filter.Name) || !containsFold(row.Address, filter.Address) {
+				continue
+			}
+			hotel := row
+			hotels = append(hotels, &hotel)
+		}
+		return nil
+	})
+
+	return sort.page(hotels, filter.Cursor, filter.Limit)
+}
+
+// Delete removes the hotel for good, cascading to its rooms, reservations, rate
+// plans and staff. The API soft-deletes with SoftDelete instead.
+func (r *HotelRepository) Delete(ctx context.Context, id int64) error {
+	return r.store.write(ctx, func(t *tables) error {
+		if _, ok := t.hotels[id]; !ok {
+			return model.NewNotFoundError("hotel with ID %d not found", id)
+		}
+
+		t.deleteHotel(id)
+		return nil
+	})
+}
+
+// SoftDelete hides the hotel and its rooms from every query. A room counts as
+// deleted while its hotel is, so Restore brings back only the rooms that were
+// not deleted on their own.
+func (r *HotelRepository) SoftDelete(ctx context.Context, id, version int64) error {
+	return r.store.write(ctx, func(t *tables) error {
+		row, ok := t.hotels[id]
+		if !ok || row.DeletedAt != nil {
+			return model.NewNotFoundError("hotel with ID %d not found", id)
+		}
+		if version != service.AnyVersion && row.Version != version {
+			return model.NewPreconditionFailedError("hotel %d has changed since version %d", id, version)
+		}
+
+		now := time.Now()
+		row.DeletedAt = &now
+		row.Version++
+		t.hotels[id] = row
+		return nil
+	})
+}
+
+// FindDeletedByID loads a soft-deleted hotel without its rooms
+func (r *HotelRepository) FindDeletedByID(ctx context.Context, id int64) (*model.Hotel, error) {
+	var hotel model.Hotel
+	err := r.store.read(func(t *tables) error {
+		var ok bool
+		if hotel, ok = t.hotels[id]; !ok || hotel.DeletedAt == nil {
+			return model.NewNotFoundError("deleted hotel with ID %d not found", id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &hotel, nil
+}
+
+// Restore brings back a soft-deleted hotel together with its rooms
+func (r *HotelRepository) Restore(ctx context.Context, id int64) error {
+	return r.store.write(ctx, func(t *tables) error {
+		row, ok := t.hotels[id]
+		if !ok || row.DeletedAt == nil {
+			return model.NewNotFoundError("deleted hotel with ID %d not found", id)
+		}
+
+		row.DeletedAt = nil
+		row.Version++
+		t.hotels[id] = row
+		return nil
+	})
+}
+
+// BumpVersion marks the hotel changed after a change of its rooms
+func (r *HotelRepository) BumpVersion(ctx context.Context, id int64) error {
+	return r.store.write(ctx, func(t *tables) error {
+		row, ok := t.hotels[id]
+		if !ok || row.DeletedAt != nil {
+			return model.NewNotFoundError("hotel with ID %d not found", id)
+		}
+
+		row.Version++
+		row.UpdatedAt = time.Now()
+		t.hotels[id] = row
+		return nil
+	})
+}
+
+// PurgeDeleted removes hotels soft-deleted before the cutoff for good and
+// returns them. A hotel with a confirmed stay yet to end is kept, as fk_room
+// would refuse to take the stay along.
+func (r *HotelRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]*model.Hotel, error) {
+	var purged []*model.Hotel
+	err := r.store.write(ctx, func(t *tables) error {
+		for id, row := range t.hotels {
+			if row.DeletedAt != nil && row.DeletedAt.Before(before) && !t.hasUpcomingStays(id) {
+				t.deleteHotel(id)
+				hotel := row
+				purged = append(purged, &hotel)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(purged, func(i, j int) bool { return purged[i].ID < purged[j].ID })
+	return purged, nil
+}
+
+// RoomRepository
+
+func (r *RoomRepository) Save(ctx context.Context, room *model.Room) error {
+	if room == nil {
+		return fmt.Errorf("room cannot be nil")
+	}
+
+	return r.store.write(ctx, func(t *tables) error {
+		if err := t.checkRoom(room); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		t.seq.rooms++
+		room.ID = t.seq.rooms
+		room.CreatedAt = now
+		room.UpdatedAt = now
+		room.Version = 1
+
+		t.rooms[room.ID] = *room
+		return nil
+	})
+}
+
+func (r *RoomRepository) Update(ctx context.Context, room *model.Room) error {
+	if room == nil {
+		return fmt.Errorf("room cannot be nil")
+	}
+	if room.ID == 0 {
+		return fmt.Errorf("room ID is required for update")
+	}
+
+	return r.store.write(ctx, func(t *tables) error {
+		row, ok := t.rooms[room.ID]
+		if !ok || t.roomDeleted(row) {
+			return model.NewNotFoundError("room with ID %d not found", room.ID)
+		}
+		if row.Version != room.Version {
+			return model.NewPreconditionFailedError("room %d has changed since version %d", room.ID, room.Version)
+		}
+		if err := t.checkRoom(room); err != nil {
+			return err
+		}
+
+		room.CreatedAt = row.CreatedAt
+		room.UpdatedAt = time.Now()
+		room.Version++
+		t.rooms[room.ID] = *room
+		return nil
+	})
+}
+
+// checkRoom enforces the foreign key to hotels and unique_room_number_per_hotel,
+// which leaves the numbers of deleted rooms free
+func (t *tables) checkRoom(room *model.Room) error {
+	if _, ok := t.hotels[room.HotelID]; !ok {
+		return model.NewNotFoundError("hotel with ID %d not found", room.HotelID)
+	}
+
+	for _, other := range t.rooms {
+		if other.ID != room.ID && other.HotelID == room.HotelID && other.Number == room.Number && other.DeletedAt == nil {
+			return model.NewConflictError("room %s already exists in hotel %d", room.Number, room.HotelID)
+		}
+	}
+
+	return nil
+}
+
+func (r *RoomRepository) FindByID(ctx context.Context, id int64) (*model.Room, error) {
+	var room model.Room
+	err := r.store.read(func(t *tables) error {
+		var ok bool
+		if room, ok = t.rooms[id]; !ok || t.roomDeleted(room) {
+			return model.NewNotFoundError("room with ID %d not found", id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &room, nil
+}
+
+func (r *RoomRepository) FindAll(ctx context.Context) ([]*model.Room, error) {
+	return r.findRooms(func(room *model.Room) bool { return true }), nil
+}
+
+func (r *RoomRepository) FindByHotelID(ctx context.Context, hotelID int64) ([]*model.Room, error) {
+	return r.findRooms(func(room *model.Room) bool { return room.HotelID == hotelID }), nil
+}
+
+func (r *RoomRepository) FindAllAvailable(ctx context.Context) ([]*model.Room, error) {
+	return r.findRooms(func(room *model.Room) bool { return room.Available }), nil
+}
+
+// findRooms returns the matching rooms ordered by hotel and number
+func (r *RoomRepository) findRooms(match func(room *model.Room) bool) []*model.Room {
+	var rooms []*model.Room
+	r.store.read(func(t *tables) error {
+		for _, row := range t.rooms {
+			room := row
+			if !t.roomDeleted(row) && match(&room) {
+				rooms = append(rooms, &room)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(rooms, func(i, j int) bool {
+		if rooms[i].HotelID != rooms[j].HotelID {
+			return rooms[i].HotelID < rooms[j].HotelID
+		}
+		return rooms[i].Number < rooms[j].Number
+	})
+	return rooms
+}
+
+func roomValues(value func(room *model.Room) string) func(room *model.Room) []string {
+	return func(room *model.Room) []string {
+		return []string{value(room), strconv.FormatInt(room.ID, 10)}
+	}
+}
+
+func roomPrice(room *model.Room) string {
+	return room.Price.Decimal()
+}
+
+func roomCapacity(room *model.Room) string {
+	return strconv.Itoa(room.Capacity)
+}
+
+var roomSorts = map[string]keyset[*model.Room]{
+	"": {
+		columns: []column{intColumn, stringColumn, intColumn},
+		values: func(room *model.Room) []string {
+			return []string{strconv.FormatInt(room.HotelID, 10), room.Number, strconv.FormatInt(room.ID, 10)}
+		},
+	},
+	"price":     {columns: []column{decimalColumn, intColumn}, values: roomValues(roomPrice)},
+	"-price":    {columns: []column{decimalColumn, intColumn}, desc: true, values: roomValues(roomPrice)},
+	"capacity":  {columns: []column{intColumn, intColumn}, values: roomValues(roomCapacity)},
+	"-capacity": {columns: []column{intColumn, intColumn}, desc: true, values: roomValues(roomCapacity)},
+}
+
+// FindAvailableForStay returns one page of open rooms that match the search and have no
+// confirmed reservation overlapping the requested stay, plus the cursor of the next page
+func (r *RoomRepository) FindAvailableForStay(ctx context.Context, search dto.StaySearch) ([]*model.Room, string, error) {
+	sort, ok := roomSorts[search.Sort]
+	if !ok {
+		return nil, "", model.NewValidationError("unsupported sort %q", search.Sort)
+	}
+
+	withinPrice := func(price model.Money, bound *model.Money, cmp func(a, b int64) bool) bool {
+		return bound == nil || (price.Currency == bound.Currency && cmp(price.Amount, bound.Amount))
+	}
+
+	var rooms []*model.Room
+	r.store.read(func(t *tables) error {
+		for _, row := range t.rooms {
+			switch {
+			case !row.Available,
+				t.roomDeleted(row),
+				search.HotelID > 0 && row.HotelID != search.HotelID,
+				search.Guests > 0 && row.Capacity < search.Guests,
+				search.Type != "" && row.Type != search.Type,
+				!withinPrice(row.Price, search.MinPrice, func(a, b int64) bool { return a >= b }),
+				!withinPrice(row.Price, search.MaxPrice, func(a, b int64) bool { return a <= b }):
+				continue
+			}
+			if !search.CheckIn.IsZero() && !search.CheckOut.IsZero() && t.hasOverlap(row.ID, 0, search.CheckIn, search.CheckOut) {
+				continue
+			}
+
+			room := row
+			rooms = append(rooms, &room)
+		}
+		return nil
+	})
+
+	return sort.page(rooms, search.Cursor, search.Limit)
+}
+
+// Delete soft-deletes the room, keeping its reservations on record. It fails
+// with ErrConflict while a confirmed stay has yet to end.
+func (r *RoomRepository) Delete(ctx context.Context, id, version int64) error {
+	return r.store.write(ctx, func(t *tables) error {
+		room, ok := t.rooms[id]
+		if !ok || t.roomDeleted(room) {
+			return model.NewNotFoundError("room with ID %d not found", id)
+		}
+		if version != service.AnyVersion && room.Version != version {
+			return model.NewPreconditionFailedError("room %d has changed since version %d", id, version)
+		}
+
+		today := today()
+		for _, reservation := range t.reservations {
+			if reservation.RoomID == id &&
+				reservation.Status == model.ReservationStatusConfirmed &&
+				reservation.CheckOut.After(today) {
+				return model.NewConflictError("room %d has upcoming reservations", id)
+			}
+		}
+
+		now := time.Now()
+		room.DeletedAt = &now
+		room.Version++
+		t.rooms[id] = room
+		return nil
+	})
+}
+
+func (r *RoomRepository) UpdateAvailability(ctx context.Context, id int64, available bool, version int64) error {
+	return r.store.write(ctx, func(t *tables) error {
+		room, ok := t.rooms[id]
+		if !ok || t.roomDeleted(room) {
+			return model.NewNotFoundError("room with ID %d not found", id)
+		}
+		if room.Version != version {
+			return model.NewPreconditionFailedError("room %d has changed since version %d", id, version)
+		}
+
+		room.Available = available
+		room.UpdatedAt = time.Now()
+		room.Version++
+		t.rooms[id] = room
+		return nil
+	})
+}
+
+// roomDeleted reports whether the room was soft-deleted, on its own or with its
+// hotel
+func (t *tables) roomDeleted(room model.Room) bool {
+	return room.DeletedAt != nil || t.hotels[room.HotelID].DeletedAt != nil
+}
+
+// containsFold matches like the Postgres ILIKE '%value%' filters
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"HotelService/domain/model"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type HotelierRepository struct {
+	store *Store
+}
+
+func NewHotelierRepository(store *Store) *HotelierRepository {
+	return &HotelierRepository{store: store}
+}
+
+func (r *HotelierRepository) Save(ctx context.Context, hotelier *model.Hotelier) error {
+	if hotelier == nil {
+		return fmt.Errorf("hotelier cannot be nil")
+	}
+
+	return r.store.write(ctx, func(t *tables) error {
+		for _, other := range t.hoteliers {
+			if other.Email == hotelier.Email {
+				return model.NewConflictError("an account with email %s already exists", hotelier.Email)
+			}
+		}
+
+		now := time.Now()
+		t.seq.hoteliers++
+		hotelier.ID = t.seq.hoteliers
+		hotelier.CreatedAt = now
+		hotelier.UpdatedAt = now
+
+		t.hoteliers[hotelier.ID] = *hotelier
+		return nil
+	})
+}
+
+func (r *HotelierRepository) FindByID(ctx context.Context, id int64) (*model.Hotelier, error) {
+	var hotelier model.Hotelier
+	err := r.store.read(func(t *tables) error {
+		var ok bool
+		if hotelier, ok = t.hoteliers[id]; !ok {
+			return model.NewNotFoundError("hotelier with ID %d not found", id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &hotelier, nil
+}
+
+func (r *HotelierRepository) FindByEmail(ctx context.Context, email string) (*model.Hotelier, error) {
+	var hotelier *model.Hotelier
+	r.store.read(func(t *tables) error {
+		for _, row := range t.hoteliers {
+			if row.Email == email {
+				found := row
+				hotelier = &found
+				break
+			}
+		}
+		return nil
+	})
+	if hotelier == nil {
+		return nil, model.NewNotFoundError("hotelier with email %s not found", email)
+	}
+
+	return hotelier, nil
+}
+
+// rolePermissions mirrors the role_permissions rows seeded by migrations 008 to 010
+var rolePermissions = map[model.Role][]model.Permission{
+	model.RoleOwner: {
+		model.PermViewHotel,
+		model.PermUpdateHotel,
+		model.PermDeleteHotel,
+		model.PermManageRooms,
+		model.PermUpdateRooms,
+		model.PermSetAvailability,
+		model.PermViewReservations,
+		model.PermViewRatePlans,
+		model.PermManageRatePlans,
+		model.PermManageStaff,
+		model.PermViewAudit,
+	},
+	model.RoleFrontDesk: {
+		model.PermViewHotel,
+		model.PermSetAvailability,
+		model.PermViewReservations,
+	},
+	model.RoleHousekeeping: {
+		model.PermViewHotel,
+		model.PermSetAvailability,
+	},
+	model.RoleRevenueManager: {
+		model.PermViewHotel,
+		model.PermUpdateRooms,
+		model.PermViewRatePlans,
+		model.PermManageRatePlans,
+	},
+}
+
+type StaffRepository struct {
+	store *Store
+}
+
+func NewStaffRepository(store *Store) *StaffRepository {
+	return &StaffRepository{store: store}
+}
+
+// Save assigns the member's role, replacing any role they already hold at the hotel
+func (r *StaffRepository) Save(ctx context.Context, member *model.StaffMember) error {
+	if member == nil {
+		return fmt.Errorf("staff member cannot be nil")
+	}
+
+	return r.store.write(ctx, func(t *tables) error {
+		if _, ok := t.hotels[member.HotelID]; !ok {
+			return model.NewNotFoundError("hotel with ID %d not found", member.HotelID)
+		}
+		if _, ok := t.hoteliers[member.HotelierID]; !ok {
+			return model.NewNotFoundError("hotelier with ID %d not found", member.HotelierID)
+		}
+		if _, ok := rolePermissions[member.Role]; !ok || member.Role == model.RoleOwner {
+			return model.NewValidationError("unknown role %s", member.Role)
+		}
+
+		key := staffKey{hotelID: member.HotelID, hotelierID: member.HotelierID}
+		if existing, ok := t.staff[key]; ok {
+			member.CreatedAt = existing.CreatedAt
+		}
+
+		t.staff[key] = model.StaffMember{
+			HotelID:    member.HotelID,
+			HotelierID: member.HotelierID,
+			Role:       member.Role,
+			CreatedAt:  member.CreatedAt,
+		}
+		return nil
+	})
+}
+
+func (r *StaffRepository) Delete(ctx context.Context, hotelID, hotelierID int64) error {
+	return r.store.write(ctx, func(t *tables) error {
+		key := staffKey{hotelID: hotelID, hotelierID: hotelierID}
+		if _, ok := t.staff[key]; !ok {
+			return model.NewNotFoundError("hotelier %d is not on the staff of hotel %d", hotelierID, hotelID)
+		}
+
+		delete(t.staff, key)
+		return nil
+	})
+}
+
+func (r *StaffRepository) FindByHotelID(ctx context.Context, hotelID int64) ([]*model.StaffMember, error) {
+	var members []*model.StaffMember
+	r.store.read(func(t *tables) error {
+		for key, row := range t.staff {
+			if key.hotelID != hotelID {
+				continue
+			}
+			member := row
+			hotelier := t.hoteliers[key.hotelierID]
+			member.Email = hotelier.Email
+			member.Name = hotelier.Name
+			members = append(members, &member)
+		}
+		return nil
+	})
+
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i], members[j]
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.HotelierID < b.HotelierID
+	})
+	return members, nil
+}
+
+func (r *StaffRepository) FindRole(ctx context.Context, hotelID, hotelierID int64) (model.Role, error) {
+	var member model.StaffMember
+	var ok bool
+	r.store.read(func(t *tables) error {
+		member, ok = t.staff[staffKey{hotelID: hotelID, hotelierID: hotelierID}]
+		return nil
+	})
+	if !ok {
+		return "", model.NewNotFoundError("hotelier %d is not on the staff of hotel %d", hotelierID, hotelID)
+	}
+
+	return member.Role, nil
+}
+
+func (r *StaffRepository) RoleHasPermission(ctx context.Context, role model.Role, permission model.Permission) (bool, error) {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"HotelService/domain/model"
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+)
+
+type IdempotencyRepository struct {
+	store *Store
+}
+
+func NewIdempotencyRepository(store *Store) *IdempotencyRepository {
+	return &IdempotencyRepository{store: store}
+}
+
+func (r *IdempotencyRepository) Save(ctx context.Context, request *model.IdempotentRequest) error {
+	if request == nil {
+		return fmt.Errorf("idempotent request cannot be nil")
+	}
+
+	return r.store.write(ctx, func(t *tables) error {
+		if _, ok := t.hoteliers[request.HotelierID]; !ok {
+			return model.NewNotFoundError("hotelier with ID %d not found", request.HotelierID)
+		}
+
+		key := idempotencyKey{hotelierID: request.HotelierID, key: request.Key}
+		if _, ok := t.idempotency[key]; ok {
+			return model.NewConflictError("idempotency key %q has already been used", request.Key)
+		}
+
+		request.CreatedAt = time.Now()
+		t.idempotency[key] = model.IdempotentRequest{
+			HotelierID:  request.HotelierID,
+			Key:         request.Key,
+			RequestHash: request.RequestHash,
+			CreatedAt:   request.CreatedAt,
+		}
+		return nil
+	})
+}
+
+func (r *IdempotencyRepository) FindByKey(ctx context.Context, hotelierID int64, key string) (*model.IdempotentRequest, error) {
+	var request model.IdempotentRequest
+	err := r.store.read(func(t *tables) error {
+		var ok bool
+		if request, ok = t.idempotency[idempotencyKey{hotelierID: hotelierID, key: key}]; !ok {
+			return model.NewNotFoundError("idempotency key %q not found", key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// Complete stores the response to a saved request
+func (r *IdempotencyRepository) Complete(ctx context.Context, request *model.IdempotentRequest) error {
+	if request == nil {
+		return fmt.Errorf("idempotent request cannot be nil")
+	}
+
+	return r.store.write(ctx, func(t *tables) error {
+		key := idempotencyKey{hotelierID: request.HotelierID, key: request.Key}
+		row, ok := t.idempotency[key]
+		if !ok {
+			return model.NewNotFoundError("idempotency key %q not found", request.Key)
+		}
+
+		row.StatusCode = request.StatusCode
+		row.ContentType = request.ContentType
+		row.Body = bytes.Clone(request.Body)
+		t.idempotency[key] = row
+		return nil
+	})
+}
+
+func (r *IdempotencyRepository) Delete(ctx context.Context, hotelierID int64, key string) error {
+	return r.store.write(ctx, func(t *tables) error {
+		delete(t.idempotency, idempotencyKey{hotelierID: hotelierID, key: key})
+		return nil
+	})
+}
+
+// PurgeBefore removes the keys saved before the cutoff and returns how many
+// were removed
+func (r *IdempotencyRepository) PurgeBefore(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := r.store.write(ctx, func(t *tables) error {
+		for key, row := range t.idempotency {
+			if row.CreatedAt.Before(before) {
+				delete(t.idempotency, key)
+				purged++
+			}
+		}
+		return nil
+	})
+	return purged, err
+}
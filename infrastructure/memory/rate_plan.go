@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"HotelService/domain/model"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type RatePlanRepository struct {
+	store *Store
+}
+
+func NewRatePlanRepository(store *Store) *RatePlanRepository {
+	return &RatePlanRepository{store: store}
+}
+
+func (r *RatePlanRepository) Save(ctx context.Context, plan *model.RatePlan) error {
+	if plan == nil {
+		return fmt.Errorf("rate plan cannot be nil")
+	}
+
+	return r.store.write(ctx, func(t *tables) error {
+		for _, other := range t.ratePlans {
+			if other.HotelID == plan.HotelID && other.Name == plan.Name {
+				return model.NewConflictError("rate plan %q already exists in hotel %d", plan.Name, plan.HotelID)
+			}
+		}
+		if _, ok := t.hotels[plan.HotelID]; !ok {
+			return model.NewNotFoundError("hotel with ID %d not found", plan.HotelID)
+		}
+
+		now := time.Now()
+		t.seq.ratePlans++
+		plan.ID = t.seq.ratePlans
+		plan.CreatedAt = now
+		plan.UpdatedAt = now
+
+		row := *plan
+		row.SeasonalRates = nil
+		row.DayModifiers = nil
+		t.ratePlans[row.ID] = row
+		return nil
+	})
+}
+
+// FindByID loads the plan together with its seasonal rates and day modifiers
+func (r *RatePlanRepository) FindByID(ctx context.Context, id int64) (*model.RatePlan, error) {
+	var plan model.RatePlan
+	err := r.store.read(func(t *tables) error {
+		var ok bool
+		if plan, ok = t.ratePlans[id]; !ok {
+			return model.NewNotFoundError("rate plan with ID %d not found", id)
+		}
+
+		for _, rate := range t.seasonalRates {
+			if rate.RatePlanID == id {
+				plan.SeasonalRates = append(plan.SeasonalRates, rate)
+			}
+		}
+		for key, multiplier := range t.dayModifiers {
+			if key.ratePlanID == id {
+				plan.DayModifiers = append(plan.DayModifiers, model.DayModifier{Weekday: key.weekday, Multiplier: multiplier})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(plan.SeasonalRates, func(i, j int) bool {
+		return plan.SeasonalRates[i].ID < plan.SeasonalRates[j].ID
+	})
+	sort.Slice(plan.DayModifiers, func(i, j int) bool {
+		return plan.DayModifiers[i].Weekday < plan.DayModifiers[j].Weekday
+	})
+
+	return &plan, nil
+}
+
+func (r *RatePlanRepository) FindByHotelID(ctx context.Context, hotelID int64) ([]*model.RatePlan, error) {
+	var plans []*model.RatePlan
+	r.store.read(func(t *tables) error {
+		for _, row := range t.ratePlans {
+			if row.HotelID == hotelID {
+				plan := row
+				plans = append(plans, &plan)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(plans, func(i, j int) bool {
+		return plans[i].Name < plans[j].Name
+	})
+	return plans, nil
+}
+
+func (r *RatePlanRepository) SaveSeasonalRate(ctx context.Context, rate *model.SeasonalRate) error {
+	if rate == nil {
+		return fmt.Errorf("seasonal rate cannot be nil")
+	}
+
+	return r.store.write(ctx, func(t *tables) error {
+		if _, ok := t.ratePlans[rate.RatePlanID]; !ok {
+			return model.NewNotFoundError("rate plan with ID %d not found", rate.RatePlanID)
+		}
+		if rate.RoomID != nil {
+			if _, ok := t.rooms[*rate.RoomID]; !ok {
+				return model.NewNotFoundError("room with ID %d not found", *rate.RoomID)
+			}
+		}
+
+		t.seq.seasonalRates++
+		rate.ID = t.seq.seasonalRates
+		rate.CreatedAt = time.Now()
+
+		row := *rate
+		if rate.RoomID != nil {
+			roomID := *rate.RoomID
+			row.RoomID = &roomID
+		}
+		t.seasonalRates[row.ID] = row
+		return nil
+	})
+}
+
+// SetDayModifier creates or replaces the modifier for the weekday
+func (r *RatePlanRepository) SetDayModifier(ctx context.Context, ratePlanID int64, modifier model.DayModifier) error {
+	return r.store.write(ctx, func(t *tables) error {
+		if _, ok := t.ratePlans[ratePlanID]; !ok {
+			return model.NewNotFoundError("rate plan with ID %d not found", ratePlanID)
+		}
+
+		t.dayModifiers[dayModifierKey{ratePlanID: ratePlanID, weekday: modifier.Weekday}] = modifier.Multiplier
+		return nil
+	})
+}
@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"HotelService/application/service"
+	"HotelService/infrastructure/memory"
+	"HotelService/infrastructure/repotest"
+	"testing"
+)
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) service.Repositories {
+		return memory.NewRepositories()
+	})
+}
@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"HotelService/domain/model"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type ReservationRepository struct {
+	store *Store
+}
+
+func NewReservationRepository(store *Store) *ReservationRepository {
+	return &ReservationRepository{store: store}
+}
+
+func (r *ReservationRepository) Save(ctx context.Context, reservation *model.Reservation) error {
+	if reservation == nil {
+		return fmt.Errorf("reservation cannot be nil")
+	}
+
+	return r.store.write(ctx, func(t *tables) error {
+		if _, ok := t.rooms[reservation.RoomID]; !ok {
+			return model.NewNotFoundError("room with ID %d not found", reservation.RoomID)
+		}
+		if !reservation.CheckOut.After(reservation.CheckIn) {
+			return fmt.Errorf("failed to save reservation: check_out must be after check_in")
+		}
+		if reservation.Status == model.ReservationStatusConfirmed &&
+			t.hasOverlap(reservation.RoomID, 0, reservation.CheckIn, reservation.CheckOut) {
+			return model.ErrReservationOverlap
+		}
+		if reservation.Code != "" {
+			for _, row := range t.reservations {
+				if row.Code == reservation.Code {
+					return fmt.Errorf("failed to save reservation: duplicate code")
+				}
+			}
+		}
+
+		now := time.Now()
+		t.seq.reservations++
+		reservation.ID = t.seq.reservations
+		reservation.CreatedAt = now
+		reservation.UpdatedAt = now
+
+		t.reservations[reservation.ID] = *reservation
+		return nil
+	})
+}
+
+func (r *ReservationRepository) FindByID(ctx context.Context, id int64) (*model.Reservation, error) {
+	var reservation model.Reservation
+	err := r.store.read(func(t *tables) error {
+		var ok bool
+		if reservation, ok = t.reservations[id]; !ok {
+			return model.NewNotFoundError("reservation with ID %d not found", id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// FindByCode returns the reservation the guest was given code for
+func (r *ReservationRepository) FindByCode(ctx context.Context, code string) (*model.Reservation, error) {
+	var reservation model.Reservation
+	err := r.store.read(func(t *tables) error {
+		for _, row := range t.reservations {
+			if code != "" && row.Code == code {
+				reservation = row
+				return nil
+			}
+		}
+		return model.NewNotFoundError("reservation not found")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+func (r *ReservationRepository) FindByRoomID(ctx context.Context, roomID int64) ([]*model.Reservation, error) {
+	var reservations []*model.Reservation
+	r.store.read(func(t *tables) error {
+		for _, row := range t.reservations {
+			if row.RoomID == roomID {
+				reservation := row
+				reservations = append(reservations, &reservation)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].CheckIn.Before(reservations[j].CheckIn)
+	})
+	return reservations, nil
+}
+
+// HasOverlap reports whether a confirmed reservation for the room intersects [checkIn, checkOut)
+func (r *ReservationRepository) HasOverlap(ctx context.Context, roomID int64, checkIn, checkOut time.Time) (bool, error) {
+	var overlap bool
+	r.store.read(func(t *tables) error {
+		overlap = t.hasOverlap(roomID, 0, checkIn, checkOut)
+		return nil
+	})
+	return overlap, nil
+}
+
+func (r *ReservationRepository) HasUpcomingStays(ctx context.Context, hotelID int64) (bool, error) {
+	var upcoming bool
+	r.store.read(func(t *tables) error {
+		upcoming = t.hasUpcomingStays(hotelID)
+		return nil
+	})
+	return upcoming, nil
+}
+
+func (r *ReservationRepository) UpdateStatus(ctx context.Context, id int64, status model.ReservationStatus) error {
+	return r.store.write(ctx, func(t *tables) error {
+		reservation, ok := t.reservations[id]
+		if !ok {
+			return model.NewNotFoundError("reservation with ID %d not found", id)
+		}
+		if status == model.ReservationStatusConfirmed &&
+			t.hasOverlap(reservation.RoomID, id, reservation.CheckIn, reservation.CheckOut) {
+			return model.ErrReservationOverlap
+		}
+
+		reservation.Status = status
+		reservation.UpdatedAt = time.Now()
+		t.reservations[id] = reservation
+		return nil
+	})
+}
+
+// hasOverlap enforces no_overlapping_reservations: whether a confirmed reservation
+// of the room other than exceptID intersects [checkIn, checkOut)
+func (t *tables) hasOverlap(roomID, exceptID int64, checkIn, checkOut time.Time) bool {
+	for _, reservation := range t.reservations {
+		if reservation.ID != exceptID &&
+			reservation.RoomID == roomID &&
+			reservation.Status == model.ReservationStatusConfirmed &&
+			reservation.CheckIn.Before(checkOut) &&
+			reservation.CheckOut.After(checkIn) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasUpcomingStays reports whether a room of the hotel has a confirmed
+// reservation that has not ended
+func (t *tables) hasUpcomingStays(hotelID int64) bool {
+	today := today()
+	for _, reservation := range t.reservations {
+		if t.rooms[reservation.RoomID].HotelID == hotelID &&
+			reservation.Status == model.ReservationStatusConfirmed &&
+			reservation.CheckOut.After(today) {
+			return true
+		}
+	}
+	return false
+}
+
+// today is the current date the way stay dates are stored: midnight UTC of the
+// local calendar day, as the service truncates them
+func today() time.Time {
+	y, m, d := time.Now().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
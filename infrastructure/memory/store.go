@@ -0,0 +1,241 @@
+// Package memory keeps every repository in process memory. It mirrors the
+// Postgres backend closely enough to run the API and tests without a database.
+package memory
+
+import (
+	"HotelService/application/service"
+	"HotelService/domain/model"
+	"context"
+	"sync"
+	"time"
+)
+
+// Store holds the tables of the in-memory backend. Repositories created from the
+// same Store share its data, so foreign keys and cascading deletes behave as
+// they do in Postgres.
+//
+// Writers are serialised; a unit of work holds the writer lock for its whole
+// duration and restores a snapshot if it fails. Readers are never blocked and
+// may observe the changes of a unit of work that has not finished yet.
+type Store struct {
+	writeMu sync.Mutex
+	mu      sync.RWMutex
+	data    tables
+}
+
+type staffKey struct {
+	hotelID    int64
+	hotelierID int64
+}
+
+type idempotencyKey struct {
+	hotelierID int64
+	key        string
+}
+
+type dayModifierKey struct {
+	ratePlanID int64
+	weekday    time.Weekday
+}
+
+type tables struct {
+	seq           sequences
+	hotels        map[int64]model.Hotel
+	rooms         map[int64]model.Room
+	reservations  map[int64]model.Reservation
+	ratePlans     map[int64]model.RatePlan
+	seasonalRates map[int64]model.SeasonalRate
+	dayModifiers  map[dayModifierKey]float64
+	hoteliers     map[int64]model.Hotelier
+	staff         map[staffKey]model.StaffMember
+	auditLog      map[int64]model.AuditEntry
+	idempotency   map[idempotencyKey]model.IdempotentRequest
+}
+
+type sequences struct {
+	hotels, rooms, reservations, ratePlans, seasonalRates, hoteliers, auditLog int64
+}
+
+func NewStore() *Store {
+	return &Store{
+		data: tables{
+			hotels:        make(map[int64]model.Hotel),
+			rooms:         make(map[int64]model.Room),
+			reservations:  make(map[int64]model.Reservation),
+			ratePlans:     make(map[int64]model.RatePlan),
+			seasonalRates: make(map[int64]model.SeasonalRate),
+			dayModifiers:  make(map[dayModifierKey]float64),
+			hoteliers:     make(map[int64]model.Hotelier),
+			staff:         make(map[staffKey]model.StaffMember),
+			auditLog:      make(map[int64]model.AuditEntry),
+			idempotency:   make(map[idempotencyKey]model.IdempotentRequest),
+		},
+	}
+}
+
+// NewRepositories returns every repository backed by a fresh Store
+func NewRepositories() service.Repositories {
+	store := NewStore()
+	return service.Repositories{
+		UnitOfWork:   NewUnitOfWork(store),
+		Hotels:       NewHotelRepository(store),
+		Rooms:        NewRoomRepository(store),
+		Reservations: NewReservationRepository(store),
+		RatePlans:    NewRatePlanRepository(store),
+		Hoteliers:    NewHotelierRepository(store),
+		Staff:        NewStaffRepository(store),
+		Audit:        NewAuditRepository(store),
+		Idempotency:  NewIdempotencyRepository(store),
+	}
+}
+
+type txKey struct{}
+
+func (s *Store) inTx(ctx context.Context) bool {
+	store, _ := ctx.Value(txKey{}).(*Store)
+	return store == s
+}
+
+// read runs fn with shared access to the tables
+func (s *Store) read(fn func(t *tables) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(&s.data)
+}
+
+// write runs fn with exclusive access to the tables. fn must check everything
+// before changing anything so a failed write leaves no trace.
+func (s *Store) write(ctx context.Context, fn func(t *tables) error) error {
+	if !s.inTx(ctx) {
+		s.writeMu.Lock()
+		defer s.writeMu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(&s.data)
+}
+
+type UnitOfWork struct {
+	store *Store
+}
+
+func NewUnitOfWork(store *Store) *UnitOfWork {
+	return &UnitOfWork{store: store}
+}
+
+// Do runs fn holding the writer lock and rolls the store back to its state before
+// fn if fn fails or panics. A nested Do joins the outer unit of work.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if u.store.inTx(ctx) {
+		return fn(ctx)
+	}
+
+	u.store.writeMu.Lock()
+	defer u.store.writeMu.Unlock()
+
+	u.store.mu.RLock()
+	snapshot := u.store.data.clone()
+	u.store.mu.RUnlock()
+
+	rollback := func() {
+		u.store.mu.Lock()
+		u.store.data = snapshot
+		u.store.mu.Unlock()
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, u.store)); err != nil {
+		rollback()
+		return err
+	}
+
+	return nil
+}
+
+func (t *tables) clone() tables {
+	return tables{
+		seq:           t.seq,
+		hotels:        cloneMap(t.hotels),
+		rooms:         cloneMap(t.rooms),
+		reservations:  cloneMap(t.reservations),
+		ratePlans:     cloneMap(t.ratePlans),
+		seasonalRates: cloneMap(t.seasonalRates),
+		dayModifiers:  cloneMap(t.dayModifiers),
+		hoteliers:     cloneMap(t.hoteliers),
+		staff:         cloneMap(t.staff),
+		auditLog:      cloneMap(t.auditLog),
+		idempotency:   cloneMap(t.idempotency),
+	}
+}
+
+// cloneMap copies a table. Rows are stored by value and never share mutable
+// state, so copying the map is enough.
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	clone := make(map[K]V, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
+
+// deleteHotel removes the hotel and everything that references it but its audit
+// log, like ON DELETE CASCADE and the repositories deleting the reservations of
+// its rooms
+func (t *tables) deleteHotel(id int64) {
+	delete(t.hotels, id)
+
+	for roomID, room := range t.rooms {
+		if room.HotelID == id {
+			t.deleteRoom(roomID)
+		}
+	}
+	for planID, plan := range t.ratePlans {
+		if plan.HotelID == id {
+			t.deleteRatePlan(planID)
+		}
+	}
+	for key := range t.staff {
+		if key.hotelID == id {
+			delete(t.staff, key)
+		}
+	}
+}
+
+// deleteRoom removes the room with its reservations when its hotel is deleted
+// for good
+func (t *tables) deleteRoom(id int64) {
+	delete(t.rooms, id)
+
+	for reservationID, reservation := range t.reservations {
+		if reservation.RoomID == id {
+			delete(t.reservations, reservationID)
+		}
+	}
+	for rateID, rate := range t.seasonalRates {
+		if rate.RoomID != nil && *rate.RoomID == id {
+			delete(t.seasonalRates, rateID)
+		}
+	}
+}
+
+func (t *tables) deleteRatePlan(id int64) {
+	delete(t.ratePlans, id)
+
+	for rateID, rate := range t.seasonalRates {
+		if rate.RatePlanID == id {
+			delete(t.seasonalRates, rateID)
+		}
+	}
+	for key := range t.dayModifiers {
+		if key.ratePlanID == id {
+			delete(t.dayModifiers, key)
+		}
+	}
+}
@@ -0,0 +1,893 @@
+// Package repotest is a conformance suite every storage backend must pass, so
+// that the service behaves the same whichever backend it runs on. A backend's
+// tests call Run with a function returning repositories over empty storage:
+//
+//	func TestRepositories(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T) service.Repositories {
+//			return memory.NewRepositories()
+//		})
+//	}
+package repotest
+
+import (
+	"HotelService/application/dto"
+	"HotelService/application/service"
+	"HotelService/domain/model"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Run exercises the hotel, room, reservation, audit, idempotency and unit of work
+// semantics the service relies on. newRepos is called once per subtest and must
+// return repositories over empty storage.
+func Run(t *testing.T, newRepos func(t *testing.T) service.Repositories) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repos service.Repositories)
+	}{
+		{"HotelSaveAndFind", testHotelSaveAndFind},
+		{"HotelUpdate", testHotelUpdate},
+		{"HotelDeleteCascades", testHotelDeleteCascades},
+		{"HotelSoftDelete", testHotelSoftDelete},
+		{"HotelPurgeDeleted", testHotelPurgeDeleted},
+		{"HotelPage", testHotelPage},
+		{"RoomRequiresHotel", testRoomRequiresHotel},
+		{"RoomNumberUniquePerHotel", testRoomNumberUniquePerHotel},
+		{"RoomAvailability", testRoomAvailability},
+		{"RoomDeleteKeepsReservations", testRoomDeleteKeepsReservations},
+		{"AvailableForStay", testAvailableForStay},
+		{"ReservationOverlap", testReservationOverlap},
+		{"ReservationCode", testReservationCode},
+		{"HasUpcomingStays", testHasUpcomingStays},
+		{"UnitOfWorkRollback", testUnitOfWorkRollback},
+		{"AuditLog", testAuditLog},
+		{"IdempotencyKeys", testIdempotencyKeys},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepos(t))
+		})
+	}
+}
+
+func testHotelSaveAndFind(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	hotel := mustSaveHotel(t, repos, "Grand Hotel")
+	if hotel.ID == 0 {
+		t.Fatal("Save did not assign an ID")
+	}
+	mustSaveRoom(t, repos, hotel.ID, "102", 15000)
+	mustSaveRoom(t, repos, hotel.ID, "101", 10000)
+
+	found, err := repos.Hotels.FindByID(ctx, hotel.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if found.Name != hotel.Name || found.Currency != hotel.Currency {
+		t.Errorf("FindByID = %+v, want name %q and currency %q", found, hotel.Name, hotel.Currency)
+	}
+	if len(found.Rooms) != 2 || found.Rooms[0].Number != "101" || found.Rooms[1].Number != "102" {
+		t.Errorf("FindByID rooms = %+v, want 101 and 102 ordered by number", found.Rooms)
+	}
+	if got := found.Rooms[1].Price; got != model.NewMoney(15000, "USD") {
+		t.Errorf("room price = %v, want 150.00 USD", got)
+	}
+
+	_, err = repos.Hotels.FindByID(ctx, hotel.ID+1000)
+	expectKind(t, "FindByID of a missing hotel", err, model.ErrNotFound)
+}
+
+func testHotelUpdate(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	hotel := mustSaveHotel(t, repos, "Old Name")
+
+	hotel.Name = "New Name"
+	hotel.Address = "New Address"
+	if err := repos.Hotels.Update(ctx, hotel); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if hotel.Version != 2 {
+		t.Errorf("version after Update = %d, want 2", hotel.Version)
+	}
+
+	found, err := repos.Hotels.FindByID(ctx, hotel.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if found.Name != "New Name" || found.Address != "New Address" || found.Version != 2 {
+		t.Errorf("after Update got %q, %q, version %d", found.Name, found.Address, found.Version)
+	}
+
+	stale := *found
+	stale.Version = 1
+	stale.Name = "Stale Name"
+	expectKind(t, "Update of a stale version", repos.Hotels.Update(ctx, &stale), model.ErrPreconditionFailed)
+
+	missing := &model.Hotel{ID: hotel.ID + 1000, Name: "x", Address: "x"}
+	expectKind(t, "Update of a missing hotel", repos.Hotels.Update(ctx, missing), model.ErrNotFound)
+
+	if err := repos.Hotels.BumpVersion(ctx, hotel.ID); err != nil {
+		t.Fatalf("BumpVersion: %v", err)
+	}
+	found, err = repos.Hotels.FindByID(ctx, hotel.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if found.Version != 3 || found.Name != "New Name" {
+		t.Errorf("after BumpVersion got %q, version %d, want %q, version 3", found.Name, found.Version, "New Name")
+	}
+	expectKind(t, "BumpVersion of a missing hotel", repos.Hotels.BumpVersion(ctx, missing.ID), model.ErrNotFound)
+}
+
+func testHotelDeleteCascades(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	hotel := mustSaveHotel(t, repos, "Doomed")
+	room := mustSaveRoom(t, repos, hotel.ID, "1", 10000)
+	reservation := mustSaveReservation(t, repos, room.ID, date(2030, 1, 1), date(2030, 1, 3))
+
+	if err := repos.Hotels.Delete(ctx, hotel.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	_, err := repos.Hotels.FindByID(ctx, hotel.ID)
+	expectKind(t, "FindByID of a deleted hotel", err, model.ErrNotFound)
+	_, err = repos.Rooms.FindByID(ctx, room.ID)
+	expectKind(t, "FindByID of a room of a deleted hotel", err, model.ErrNotFound)
+	_, err = repos.Reservations.FindByID(ctx, reservation.ID)
+	expectKind(t, "FindByID of a reservation of a deleted hotel", err, model.ErrNotFound)
+
+	expectKind(t, "second Delete", repos.Hotels.Delete(ctx, hotel.ID), model.ErrNotFound)
+}
+
+func testHotelSoftDelete(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	hotel := mustSaveHotel(t, repos, "Hidden")
+	room := mustSaveRoom(t, repos, hotel.ID, "1", 10000)
+	other := mustSaveHotel(t, repos, "Visible")
+	mustSaveRoom(t, repos, other.ID, "2", 10000)
+
+	_, err := repos.Hotels.FindDeletedByID(ctx, hotel.ID)
+	expectKind(t, "FindDeletedByID of a live hotel", err, model.ErrNotFound)
+	expectKind(t, "Restore of a live hotel", repos.Hotels.Restore(ctx, hotel.ID), model.ErrNotFound)
+
+	err = repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		return repos.Hotels.SoftDelete(ctx, hotel.ID, hotel.Version+1)
+	})
+	expectKind(t, "SoftDelete of a stale version", err, model.ErrPreconditionFailed)
+
+	err = repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		return repos.Hotels.SoftDelete(ctx, hotel.ID, hotel.Version)
+	})
+	if err != nil {
+		t.Fatalf("SoftDelete: %v", err)
+	}
+
+	_, err = repos.Hotels.FindByID(ctx, hotel.ID)
+	expectKind(t, "FindByID of a soft-deleted hotel", err, model.ErrNotFound)
+	_, err = repos.Rooms.FindByID(ctx, room.ID)
+	expectKind(t, "FindByID of a room of a soft-deleted hotel", err, model.ErrNotFound)
+	expectKind(t, "UpdateAvailability of a room of a soft-deleted hotel", repos.Rooms.UpdateAvailability(ctx, room.ID, false, room.Version), model.ErrNotFound)
+	expectKind(t, "second SoftDelete", repos.Hotels.SoftDelete(ctx, hotel.ID, service.AnyVersion), model.ErrNotFound)
+
+	hotels, err := repos.Hotels.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if len(hotels) != 1 || hotels[0].ID != other.ID {
+		t.Errorf("FindAll = %d hotels, want only the live one", len(hotels))
+	}
+	page, _, err := repos.Hotels.FindPage(ctx, dto.HotelFilter{PageRequest: dto.PageRequest{Limit: 10}})
+	if err != nil {
+		t.Fatalf("FindPage: %v", err)
+	}
+	if len(page) != 1 || page[0].ID != other.ID {
+		t.Errorf("FindPage = %d hotels, want only the live one", len(page))
+	}
+	rooms, err := repos.Rooms.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll rooms: %v", err)
+	}
+	if got := roomNumbers(rooms); got != "[2]" {
+		t.Errorf("FindAll rooms = %s, want [2]", got)
+	}
+	rooms, _, err = repos.Rooms.FindAvailableForStay(ctx, dto.StaySearch{PageRequest: dto.PageRequest{Limit: 10}})
+	if err != nil {
+		t.Fatalf("FindAvailableForStay: %v", err)
+	}
+	if got := roomNumbers(rooms); got != "[2]" {
+		t.Errorf("FindAvailableForStay = %s, want [2]", got)
+	}
+
+	deleted, err := repos.Hotels.FindDeletedByID(ctx, hotel.ID)
+	if err != nil {
+		t.Fatalf("FindDeletedByID: %v", err)
+	}
+	if deleted.DeletedAt == nil {
+		t.Error("FindDeletedByID returned a hotel without DeletedAt")
+	}
+
+	err = repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		return repos.Hotels.Restore(ctx, hotel.ID)
+	})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	found, err := repos.Hotels.FindByID(ctx, hotel.ID)
+	if err != nil {
+		t.Fatalf("FindByID of a restored hotel: %v", err)
+	}
+	if found.DeletedAt != nil || len(found.Rooms) != 1 {
+		t.Errorf("restored hotel = %+v, want it live with its room", found)
+	}
+	if _, err := repos.Rooms.FindByID(ctx, room.ID); err != nil {
+		t.Errorf("FindByID of a restored room: %v", err)
+	}
+}
+
+func testHotelPurgeDeleted(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	deleted := mustSaveHotel(t, repos, "Deleted")
+	room := mustSaveRoom(t, repos, deleted.ID, "1", 10000)
+	past := mustSaveReservation(t, repos, room.ID, date(2020, 1, 1), date(2020, 1, 3))
+	cancelled := mustSaveReservation(t, repos, room.ID, date(2030, 1, 1), date(2030, 1, 3))
+	if err := repos.Reservations.UpdateStatus(ctx, cancelled.ID, model.ReservationStatusCancelled); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	busy := mustSaveHotel(t, repos, "Busy")
+	busyRoom := mustSaveRoom(t, repos, busy.ID, "1", 10000)
+	upcoming := mustSaveReservation(t, repos, busyRoom.ID, date(2030, 1, 1), date(2030, 1, 3))
+	live := mustSaveHotel(t, repos, "Live")
+
+	actor := &model.Hotelier{Email: "purger@example.com", Name: "Purger", PasswordHash: "x"}
+	if err := repos.Hoteliers.Save(ctx, actor); err != nil {
+		t.Fatalf("Save hotelier: %v", err)
+	}
+	entry := &model.AuditEntry{HotelID: deleted.ID, ActorID: actor.ID, Action: model.AuditDelete, EntityType: model.AuditHotel, EntityID: deleted.ID}
+	if err := repos.Audit.Save(ctx, entry); err != nil {
+		t.Fatalf("Save audit entry: %v", err)
+	}
+
+	for _, hotel := range []*model.Hotel{deleted, busy} {
+		if err := repos.Hotels.SoftDelete(ctx, hotel.ID, service.AnyVersion); err != nil {
+			t.Fatalf("SoftDelete: %v", err)
+		}
+	}
+
+	purged, err := repos.Hotels.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeDeleted: %v", err)
+	}
+	if len(purged) != 0 {
+		t.Errorf("PurgeDeleted before the deletion purged %d hotels, want 0", len(purged))
+	}
+
+	err = repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		purged, err = repos.Hotels.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("PurgeDeleted: %v", err)
+	}
+	if len(purged) != 1 || purged[0].ID != deleted.ID || purged[0].Name != deleted.Name {
+		t.Fatalf("PurgeDeleted purged %+v, want only the hotel without upcoming stays", purged)
+	}
+
+	_, err = repos.Hotels.FindDeletedByID(ctx, deleted.ID)
+	expectKind(t, "FindDeletedByID of a purged hotel", err, model.ErrNotFound)
+	for _, reservation := range []*model.Reservation{past, cancelled} {
+		_, err = repos.Reservations.FindByID(ctx, reservation.ID)
+		expectKind(t, "FindByID of a reservation of a purged hotel", err, model.ErrNotFound)
+	}
+	entries, _, err := repos.Audit.FindPage(ctx, dto.AuditFilter{HotelID: deleted.ID, PageRequest: dto.PageRequest{Limit: 10}})
+	if err != nil {
+		t.Fatalf("FindPage: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != entry.ID {
+		t.Errorf("audit log of a purged hotel = %d entries, want it kept", len(entries))
+	}
+
+	if _, err := repos.Hotels.FindDeletedByID(ctx, busy.ID); err != nil {
+		t.Errorf("FindDeletedByID of a hotel with an upcoming stay after purge: %v", err)
+	}
+	if _, err := repos.Reservations.FindByID(ctx, upcoming.ID); err != nil {
+		t.Errorf("FindByID of an upcoming stay after purge: %v", err)
+	}
+	if _, err := repos.Hotels.FindByID(ctx, live.ID); err != nil {
+		t.Errorf("FindByID of a live hotel after purge: %v", err)
+	}
+
+	// The purge job records the purge itself, as no hotelier
+	purge := &model.AuditEntry{HotelID: deleted.ID, Action: model.AuditPurge, EntityType: model.AuditHotel, EntityID: deleted.ID}
+	if err := repos.Audit.Save(ctx, purge); err != nil {
+		t.Fatalf("Save audit entry without an actor: %v", err)
+	}
+	entries, _, err = repos.Audit.FindPage(ctx, dto.AuditFilter{HotelID: deleted.ID, PageRequest: dto.PageRequest{Limit: 10}})
+	if err != nil {
+		t.Fatalf("FindPage: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != model.AuditPurge || entries[0].ActorID != 0 {
+		t.Errorf("newest audit entry = %+v, want the purge without an actor", entries[0])
+	}
+}
+
+func testHasUpcomingStays(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	hotel := mustSaveHotel(t, repos, "Hotel")
+	room := mustSaveRoom(t, repos, hotel.ID, "1", 10000)
+	other := mustSaveHotel(t, repos, "Other")
+	mustSaveReservation(t, repos, mustSaveRoom(t, repos, other.ID, "1", 10000).ID, date(2030, 1, 1), date(2030, 1, 3))
+
+	mustSaveReservation(t, repos, room.ID, date(2020, 1, 1), date(2020, 1, 3))
+	upcoming, err := repos.Reservations.HasUpcomingStays(ctx, hotel.ID)
+	if err != nil {
+		t.Fatalf("HasUpcomingStays: %v", err)
+	}
+	if upcoming {
+		t.Error("HasUpcomingStays with a past stay only = true, want false")
+	}
+
+	reservation := mustSaveReservation(t, repos, room.ID, date(2030, 1, 1), date(2030, 1, 3))
+	if upcoming, err = repos.Reservations.HasUpcomingStays(ctx, hotel.ID); err != nil || !upcoming {
+		t.Errorf("HasUpcomingStays with a confirmed stay ahead = %v, %v; want true", upcoming, err)
+	}
+
+	if err := repos.Reservations.UpdateStatus(ctx, reservation.ID, model.ReservationStatusCancelled); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if upcoming, err = repos.Reservations.HasUpcomingStays(ctx, hotel.ID); err != nil || upcoming {
+		t.Errorf("HasUpcomingStays with the stay cancelled = %v, %v; want false", upcoming, err)
+	}
+}
+
+func testHotelPage(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	for _, name := range []string{"Delta Inn", "alpha lodge", "Charlie Hotel", "Bravo Inn", "Echo Inn"} {
+		mustSaveHotel(t, repos, name)
+	}
+
+	var names []string
+	filter := dto.HotelFilter{PageRequest: dto.PageRequest{Sort: "name", Limit: 2}}
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination does not terminate")
+		}
+		hotels, next, err := repos.Hotels.FindPage(ctx, filter)
+		if err != nil {
+			t.Fatalf("FindPage: %v", err)
+		}
+		if len(hotels) > filter.Limit {
+			t.Fatalf("FindPage returned %d hotels, limit is %d", len(hotels), filter.Limit)
+		}
+		for _, hotel := range hotels {
+			names = append(names, hotel.Name)
+		}
+		if next == "" {
+			break
+		}
+		filter.Cursor = next
+	}
+
+	want := []string{"Bravo Inn", "Charlie Hotel", "Delta Inn", "Echo Inn", "alpha lodge"}
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		// Postgres orders by collation; accept a case-insensitive order too
+		alt := []string{"alpha lodge", "Bravo Inn", "Charlie Hotel", "Delta Inn", "Echo Inn"}
+		if fmt.Sprint(names) != fmt.Sprint(alt) {
+			t.Errorf("paged names = %v, want %v", names, want)
+		}
+	}
+
+	hotels, _, err := repos.Hotels.FindPage(ctx, dto.HotelFilter{Name: "INN", PageRequest: dto.PageRequest{Limit: 10}})
+	if err != nil {
+		t.Fatalf("FindPage with name filter: %v", err)
+	}
+	if len(hotels) != 3 {
+		t.Errorf("name filter INN matched %d hotels, want 3", len(hotels))
+	}
+
+	_, _, err = repos.Hotels.FindPage(ctx, dto.HotelFilter{PageRequest: dto.PageRequest{Sort: "bogus", Limit: 10}})
+	expectKind(t, "FindPage with an unknown sort", err, model.ErrValidation)
+
+	_, _, err = repos.Hotels.FindPage(ctx, dto.HotelFilter{PageRequest: dto.PageRequest{Limit: 10, Cursor: "not a cursor"}})
+	expectKind(t, "FindPage with a malformed cursor", err, model.ErrValidation)
+}
+
+func testRoomRequiresHotel(t *testing.T, repos service.Repositories) {
+	room := &model.Room{HotelID: 999, Number: "1", Type: "Single", Price: model.NewMoney(100, "USD"), Capacity: 1}
+	expectKind(t, "Save of a room in a missing hotel", repos.Rooms.Save(context.Background(), room), model.ErrNotFound)
+}
+
+func testRoomNumberUniquePerHotel(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	first := mustSaveHotel(t, repos, "First")
+	second := mustSaveHotel(t, repos, "Second")
+	mustSaveRoom(t, repos, first.ID, "101", 10000)
+	other := mustSaveRoom(t, repos, first.ID, "102", 10000)
+
+	duplicate := &model.Room{HotelID: first.ID, Number: "101", Type: "Single", Price: model.NewMoney(100, "USD"), Capacity: 1}
+	expectKind(t, "Save of a duplicate room number", repos.Rooms.Save(ctx, duplicate), model.ErrConflict)
+
+	mustSaveRoom(t, repos, second.ID, "101", 10000)
+
+	other.Number = "101"
+	expectKind(t, "Update to a duplicate room number", repos.Rooms.Update(ctx, other), model.ErrConflict)
+}
+
+func testRoomAvailability(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	hotel := mustSaveHotel(t, repos, "Hotel")
+	room := mustSaveRoom(t, repos, hotel.ID, "1", 10000)
+
+	if err := repos.Rooms.UpdateAvailability(ctx, room.ID, false, room.Version); err != nil {
+		t.Fatalf("UpdateAvailability: %v", err)
+	}
+	expectKind(t, "UpdateAvailability of a stale version", repos.Rooms.UpdateAvailability(ctx, room.ID, true, room.Version), model.ErrPreconditionFailed)
+	expectKind(t, "Update of a stale version", repos.Rooms.Update(ctx, room), model.ErrPreconditionFailed)
+
+	available, err := repos.Rooms.FindAllAvailable(ctx)
+	if err != nil {
+		t.Fatalf("FindAllAvailable: %v", err)
+	}
+	if len(available) != 0 {
+		t.Errorf("FindAllAvailable returned %d rooms, want 0", len(available))
+	}
+
+	expectKind(t, "UpdateAvailability of a missing room", repos.Rooms.UpdateAvailability(ctx, room.ID+1000, true, 1), model.ErrNotFound)
+	expectKind(t, "Delete of a missing room", repos.Rooms.Delete(ctx, room.ID+1000, service.AnyVersion), model.ErrNotFound)
+}
+
+func testRoomDeleteKeepsReservations(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	hotel := mustSaveHotel(t, repos, "Hotel")
+	room := mustSaveRoom(t, repos, hotel.ID, "1", 10000)
+	past := mustSaveReservation(t, repos, room.ID, date(2020, 1, 1), date(2020, 1, 3))
+	upcoming := mustSaveReservation(t, repos, room.ID, date(2030, 1, 1), date(2030, 1, 3))
+
+	err := repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		return repos.Rooms.Delete(ctx, room.ID, service.AnyVersion)
+	})
+	expectKind(t, "Delete of a room with an upcoming stay", err, model.ErrConflict)
+	if _, err := repos.Reservations.FindByID(ctx, upcoming.ID); err != nil {
+		t.Fatalf("FindByID of the upcoming stay after a refused Delete: %v", err)
+	}
+	if _, err := repos.Reservations.FindByID(ctx, past.ID); err != nil {
+		t.Fatalf("FindByID of the past stay after a refused Delete: %v", err)
+	}
+
+	if err := repos.Reservations.UpdateStatus(ctx, upcoming.ID, model.ReservationStatusCancelled); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	err = repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		return repos.Rooms.Delete(ctx, room.ID, room.Version+1)
+	})
+	expectKind(t, "Delete of a stale version", err, model.ErrPreconditionFailed)
+	if _, err := repos.Reservations.FindByID(ctx, past.ID); err != nil {
+		t.Fatalf("FindByID of the past stay after a refused Delete: %v", err)
+	}
+
+	err = repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		return repos.Rooms.Delete(ctx, room.ID, room.Version)
+	})
+	if err != nil {
+		t.Fatalf("Delete of a room without upcoming stays: %v", err)
+	}
+
+	_, err = repos.Rooms.FindByID(ctx, room.ID)
+	expectKind(t, "FindByID of a deleted room", err, model.ErrNotFound)
+	if _, err := repos.Reservations.FindByID(ctx, past.ID); err != nil {
+		t.Fatalf("FindByID of a past stay of a deleted room: %v", err)
+	}
+
+	// the number of a deleted room is free again
+	mustSaveRoom(t, repos, hotel.ID, "1", 20000)
+
+	// restoring the hotel brings back only the rooms deleted with it
+	err = repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		return repos.Hotels.SoftDelete(ctx, hotel.ID, service.AnyVersion)
+	})
+	if err != nil {
+		t.Fatalf("SoftDelete: %v", err)
+	}
+	err = repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		return repos.Hotels.Restore(ctx, hotel.ID)
+	})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	rooms, err := repos.Rooms.FindByHotelID(ctx, hotel.ID)
+	if err != nil {
+		t.Fatalf("FindByHotelID: %v", err)
+	}
+	if len(rooms) != 1 || rooms[0].ID == room.ID {
+		t.Errorf("rooms after Restore = %d, want only the new room 1", len(rooms))
+	}
+	_, err = repos.Rooms.FindByID(ctx, room.ID)
+	expectKind(t, "FindByID of a room deleted before its hotel", err, model.ErrNotFound)
+}
+
+func testAvailableForStay(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	hotel := mustSaveHotel(t, repos, "Hotel")
+	booked := mustSaveRoom(t, repos, hotel.ID, "1", 10000)
+	mustSaveRoom(t, repos, hotel.ID, "2", 20000)
+	closed := mustSaveRoom(t, repos, hotel.ID, "3", 30000)
+	cancelled := mustSaveRoom(t, repos, hotel.ID, "4", 40000)
+
+	if err := repos.Rooms.UpdateAvailability(ctx, closed.ID, false, closed.Version); err != nil {
+		t.Fatalf("UpdateAvailability: %v", err)
+	}
+	mustSaveReservation(t, repos, booked.ID, date(2030, 1, 1), date(2030, 1, 5))
+	reservation := mustSaveReservation(t, repos, cancelled.ID, date(2030, 1, 1), date(2030, 1, 5))
+	if err := repos.Reservations.UpdateStatus(ctx, reservation.ID, model.ReservationStatusCancelled); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	search := dto.StaySearch{
+		CheckIn:     date(2030, 1, 4),
+		CheckOut:    date(2030, 1, 6),
+		PageRequest: dto.PageRequest{Limit: 10},
+	}
+	rooms, _, err := repos.Rooms.FindAvailableForStay(ctx, search)
+	if err != nil {
+		t.Fatalf("FindAvailableForStay: %v", err)
+	}
+	if got := roomNumbers(rooms); got != "[2 4]" {
+		t.Errorf("rooms free 2030-01-04..06 = %s, want [2 4]", got)
+	}
+
+	// check-out day is free for the next guest
+	search.CheckIn, search.CheckOut = date(2030, 1, 5), date(2030, 1, 7)
+	rooms, _, err = repos.Rooms.FindAvailableForStay(ctx, search)
+	if err != nil {
+		t.Fatalf("FindAvailableForStay: %v", err)
+	}
+	if got := roomNumbers(rooms); got != "[1 2 4]" {
+		t.Errorf("rooms free 2030-01-05..07 = %s, want [1 2 4]", got)
+	}
+
+	maxPrice := model.NewMoney(25000, "USD")
+	search.MaxPrice = &maxPrice
+	search.Sort = "-price"
+	rooms, _, err = repos.Rooms.FindAvailableForStay(ctx, search)
+	if err != nil {
+		t.Fatalf("FindAvailableForStay: %v", err)
+	}
+	if got := roomNumbers(rooms); got != "[2 1]" {
+		t.Errorf("rooms up to 250.00 by descending price = %s, want [2 1]", got)
+	}
+}
+
+func testReservationOverlap(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	hotel := mustSaveHotel(t, repos, "Hotel")
+	room := mustSaveRoom(t, repos, hotel.ID, "1", 10000)
+	first := mustSaveReservation(t, repos, room.ID, date(2030, 1, 1), date(2030, 1, 5))
+
+	overlapping := &model.Reservation{
+		RoomID:     room.ID,
+		GuestName:  "Second Guest",
+		GuestEmail: "second@example.com",
+		CheckIn:    date(2030, 1, 4),
+		CheckOut:   date(2030, 1, 8),
+		Status:     model.ReservationStatusConfirmed,
+	}
+	err := repos.Reservations.Save(ctx, overlapping)
+	if !errors.Is(err, model.ErrReservationOverlap) {
+		t.Fatalf("Save of an overlapping stay = %v, want ErrReservationOverlap", err)
+	}
+
+	overlap, err := repos.Reservations.HasOverlap(ctx, room.ID, date(2030, 1, 5), date(2030, 1, 6))
+	if err != nil {
+		t.Fatalf("HasOverlap: %v", err)
+	}
+	if overlap {
+		t.Error("HasOverlap reports the check-out day as taken")
+	}
+
+	if err := repos.Reservations.UpdateStatus(ctx, first.ID, model.ReservationStatusCancelled); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if err := repos.Reservations.Save(ctx, overlapping); err != nil {
+		t.Fatalf("Save after cancelling the first stay: %v", err)
+	}
+
+	reservations, err := repos.Reservations.FindByRoomID(ctx, room.ID)
+	if err != nil {
+		t.Fatalf("FindByRoomID: %v", err)
+	}
+	if len(reservations) != 2 || reservations[0].ID != first.ID {
+		t.Errorf("FindByRoomID = %d reservations, want 2 ordered by check-in", len(reservations))
+	}
+}
+
+func testReservationCode(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	hotel := mustSaveHotel(t, repos, "Hotel")
+	room := mustSaveRoom(t, repos, hotel.ID, "1", 10000)
+
+	// Bookings without a code must not collide with each other
+	uncoded := mustSaveReservation(t, repos, room.ID, date(2030, 1, 1), date(2030, 1, 2))
+	mustSaveReservation(t, repos, room.ID, date(2030, 1, 2), date(2030, 1, 3))
+
+	coded := &model.Reservation{
+		RoomID:     room.ID,
+		Code:       "GUESTCODE1234567",
+		GuestName:  "Guest",
+		GuestEmail: "guest@example.com",
+		CheckIn:    date(2030, 1, 3),
+		CheckOut:   date(2030, 1, 4),
+		Status:     model.ReservationStatusConfirmed,
+	}
+	if err := repos.Reservations.Save(ctx, coded); err != nil {
+		t.Fatalf("Save with a code: %v", err)
+	}
+
+	found, err := repos.Reservations.FindByCode(ctx, coded.Code)
+	if err != nil {
+		t.Fatalf("FindByCode: %v", err)
+	}
+	if found.ID != coded.ID || found.Code != coded.Code {
+		t.Errorf("FindByCode = reservation %d with code %q, want %d with %q", found.ID, found.Code, coded.ID, coded.Code)
+	}
+
+	_, err = repos.Reservations.FindByCode(ctx, "NOSUCHCODE123456")
+	expectKind(t, "FindByCode of an unknown code", err, model.ErrNotFound)
+	_, err = repos.Reservations.FindByCode(ctx, "")
+	expectKind(t, "FindByCode of an empty code", err, model.ErrNotFound)
+
+	found, err = repos.Reservations.FindByID(ctx, uncoded.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if found.Code != "" {
+		t.Errorf("FindByID of a reservation without a code = code %q, want none", found.Code)
+	}
+}
+
+func testUnitOfWorkRollback(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	errAbort := errors.New("abort")
+
+	var hotelID int64
+	err := repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		hotel := &model.Hotel{Name: "Rolled Back", Address: "Nowhere", Currency: "USD"}
+		if err := repos.Hotels.Save(ctx, hotel); err != nil {
+			return err
+		}
+		hotelID = hotel.ID
+
+		// nested units of work join the outer one
+		return repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+			room := &model.Room{HotelID: hotel.ID, Number: "1", Type: "Single", Price: model.NewMoney(100, "USD"), Capacity: 1}
+			if err := repos.Rooms.Save(ctx, room); err != nil {
+				return err
+			}
+			return errAbort
+		})
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("Do = %v, want the error returned by fn", err)
+	}
+
+	_, err = repos.Hotels.FindByID(ctx, hotelID)
+	expectKind(t, "FindByID of a rolled back hotel", err, model.ErrNotFound)
+
+	rooms, err := repos.Rooms.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if len(rooms) != 0 {
+		t.Errorf("FindAll after rollback returned %d rooms, want 0", len(rooms))
+	}
+
+	err = repos.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		return repos.Hotels.Save(ctx, &model.Hotel{Name: "Committed", Address: "Somewhere", Currency: "USD"})
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	hotels, err := repos.Hotels.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if len(hotels) != 1 {
+		t.Errorf("FindAll after commit returned %d hotels, want 1", len(hotels))
+	}
+}
+
+func testAuditLog(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	hotel := mustSaveHotel(t, repos, "Audited")
+	other := mustSaveHotel(t, repos, "Elsewhere")
+	actor := &model.Hotelier{Email: "auditor@example.com", Name: "Auditor", PasswordHash: "x"}
+	if err := repos.Hoteliers.Save(ctx, actor); err != nil {
+		t.Fatalf("Save hotelier: %v", err)
+	}
+
+	save := func(hotelID int64, action model.AuditAction, entityType model.AuditEntity, entityID int64) *model.AuditEntry {
+		t.Helper()
+		entry := &model.AuditEntry{
+			HotelID:    hotelID,
+			ActorID:    actor.ID,
+			Action:     action,
+			EntityType: entityType,
+			EntityID:   entityID,
+			Changes: map[string]model.FieldChange{
+				"price": {Before: []byte(`{"amount":"80.00","currency":"USD"}`), After: []byte(`{"amount":"95.00","currency":"USD"}`)},
+			},
+		}
+		if err := repos.Audit.Save(ctx, entry); err != nil {
+			t.Fatalf("Save audit entry: %v", err)
+		}
+		return entry
+	}
+	first := save(hotel.ID, model.AuditCreate, model.AuditRoom, 1)
+	save(hotel.ID, model.AuditUpdate, model.AuditRoom, 1)
+	save(hotel.ID, model.AuditUpdate, model.AuditHotel, hotel.ID)
+	save(other.ID, model.AuditUpdate, model.AuditHotel, other.ID)
+
+	page, next, err := repos.Audit.FindPage(ctx, dto.AuditFilter{HotelID: hotel.ID, PageRequest: dto.PageRequest{Limit: 2}})
+	if err != nil {
+		t.Fatalf("FindPage: %v", err)
+	}
+	if len(page) != 2 || next == "" || page[0].EntityType != model.AuditHotel {
+		t.Fatalf("first page = %d entries, cursor %q; want the 2 newest and a cursor", len(page), next)
+	}
+	if got := string(page[1].Changes["price"].After); got != `{"amount":"95.00","currency":"USD"}` {
+		t.Errorf("stored change = %s", got)
+	}
+
+	page, next, err = repos.Audit.FindPage(ctx, dto.AuditFilter{HotelID: hotel.ID, PageRequest: dto.PageRequest{Limit: 2, Cursor: next}})
+	if err != nil {
+		t.Fatalf("FindPage with cursor: %v", err)
+	}
+	if len(page) != 1 || next != "" || page[0].ID != first.ID {
+		t.Errorf("last page = %d entries, cursor %q; want only the oldest entry", len(page), next)
+	}
+
+	filtered, _, err := repos.Audit.FindPage(ctx, dto.AuditFilter{
+		HotelID:     hotel.ID,
+		Action:      model.AuditUpdate,
+		EntityType:  model.AuditRoom,
+		EntityID:    1,
+		ActorID:     actor.ID,
+		Since:       time.Now().Add(-time.Hour),
+		Until:       time.Now().Add(time.Hour),
+		PageRequest: dto.PageRequest{Limit: 10},
+	})
+	if err != nil {
+		t.Fatalf("FindPage with filters: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].Action != model.AuditUpdate || filtered[0].EntityType != model.AuditRoom {
+		t.Errorf("filtered = %d entries, want the room update only", len(filtered))
+	}
+
+	none, _, err := repos.Audit.FindPage(ctx, dto.AuditFilter{HotelID: hotel.ID, Until: time.Now().Add(-time.Hour), PageRequest: dto.PageRequest{Limit: 10}})
+	if err != nil {
+		t.Fatalf("FindPage until: %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("FindPage until an hour ago = %d entries, want 0", len(none))
+	}
+}
+
+func testIdempotencyKeys(t *testing.T, repos service.Repositories) {
+	ctx := context.Background()
+	hotelier := &model.Hotelier{Email: "retry@example.com", Name: "Retry", PasswordHash: "x"}
+	if err := repos.Hoteliers.Save(ctx, hotelier); err != nil {
+		t.Fatalf("Save hotelier: %v", err)
+	}
+
+	request := &model.IdempotentRequest{HotelierID: hotelier.ID, Key: "create-1", RequestHash: "hash"}
+	if err := repos.Idempotency.Save(ctx, request); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	again := &model.IdempotentRequest{HotelierID: hotelier.ID, Key: "create-1", RequestHash: "other"}
+	expectKind(t, "Save of a used key", repos.Idempotency.Save(ctx, again), model.ErrConflict)
+
+	found, err := repos.Idempotency.FindByKey(ctx, hotelier.ID, "create-1")
+	if err != nil {
+		t.Fatalf("FindByKey: %v", err)
+	}
+	if found.RequestHash != "hash" || found.Completed() {
+		t.Errorf("FindByKey = %+v, want the pending request with hash %q", found, "hash")
+	}
+
+	request.StatusCode = 201
+	request.ContentType = "application/json"
+	request.Body = []byte(`{"id":1}`)
+	if err := repos.Idempotency.Complete(ctx, request); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	found, err = repos.Idempotency.FindByKey(ctx, hotelier.ID, "create-1")
+	if err != nil {
+		t.Fatalf("FindByKey: %v", err)
+	}
+	if found.StatusCode != 201 || found.ContentType != "application/json" || string(found.Body) != `{"id":1}` {
+		t.Errorf("completed request = %d %q %s", found.StatusCode, found.ContentType, found.Body)
+	}
+
+	_, err = repos.Idempotency.FindByKey(ctx, hotelier.ID+1000, "create-1")
+	expectKind(t, "FindByKey of another hotelier", err, model.ErrNotFound)
+
+	if err := repos.Idempotency.Delete(ctx, hotelier.ID, "create-1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repos.Idempotency.Save(ctx, request); err != nil {
+		t.Fatalf("Save after Delete: %v", err)
+	}
+
+	purged, err := repos.Idempotency.PurgeBefore(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeBefore: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeBefore removed %d keys, want 1", purged)
+	}
+	_, err = repos.Idempotency.FindByKey(ctx, hotelier.ID, "create-1")
+	expectKind(t, "FindByKey of a purged key", err, model.ErrNotFound)
+}
+
+func mustSaveHotel(t *testing.T, repos service.Repositories, name string) *model.Hotel {
+	t.Helper()
+	hotel := &model.Hotel{Name: name, Address: name + " Street 1", Currency: "USD"}
+	if err := repos.Hotels.Save(context.Background(), hotel); err != nil {
+		t.Fatalf("Save hotel %q: %v", name, err)
+	}
+	return hotel
+}
+
+func mustSaveRoom(t *testing.T, repos service.Repositories, hotelID int64, number string, cents int64) *model.Room {
+	t.Helper()
+	room := &model.Room{
+		HotelID:   hotelID,
+		Number:    number,
+		Type:      "Double",
+		Price:     model.NewMoney(cents, "USD"),
+		Capacity:  2,
+		Available: true,
+	}
+	if err := repos.Rooms.Save(context.Background(), room); err != nil {
+		t.Fatalf("Save room %s: %v", number, err)
+	}
+	return room
+}
+
+func mustSaveReservation(t *testing.T, repos service.Repositories, roomID int64, checkIn, checkOut time.Time) *model.Reservation {
+	t.Helper()
+	reservation := &model.Reservation{
+		RoomID:     roomID,
+		GuestName:  "Guest",
+		GuestEmail: "guest@example.com",
+		CheckIn:    checkIn,
+		CheckOut:   checkOut,
+		Status:     model.ReservationStatusConfirmed,
+	}
+	if err := repos.Reservations.Save(context.Background(), reservation); err != nil {
+		t.Fatalf("Save reservation: %v", err)
+	}
+	return reservation
+}
+
+func expectKind(t *testing.T, what string, err, kind error) {
+	t.Helper()
+	if !errors.Is(err, kind) {
+		t.Errorf("%s = %v, want %v", what, err, kind)
+	}
+}
+
+func roomNumbers(rooms []*model.Room) string {
+	numbers := make([]string, len(rooms))
+	for i, room := range rooms {
+		numbers[i] = room.Number
+	}
+	return fmt.Sprint(numbers)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}